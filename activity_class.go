@@ -0,0 +1,151 @@
+// This file contains decoding of DailyActivity.Class5Min into typed activity class segments.
+//
+// Class5Min holds a digit per 5 minutes of the activity day, starting at DailyActivity.Timestamp:
+// 0 = non wear, 1 = rest, 2 = inactive, 3 = low activity, 4 = medium activity, 5 = high activity.
+
+package go_oura
+
+import (
+	"fmt"
+	"time"
+)
+
+// ActivityClassInterval is the duration covered by each digit of DailyActivity.Class5Min.
+const ActivityClassInterval = 5 * time.Minute
+
+// ActivityClass is an activity class as encoded in DailyActivity.Class5Min.
+type ActivityClass int
+
+const (
+	ActivityClassNonWear  ActivityClass = 0
+	ActivityClassRest     ActivityClass = 1
+	ActivityClassInactive ActivityClass = 2
+	ActivityClassLow      ActivityClass = 3
+	ActivityClassMedium   ActivityClass = 4
+	ActivityClassHigh     ActivityClass = 5
+)
+
+// ActivityClassValues returns every ActivityClass in encoding order.
+func ActivityClassValues() []ActivityClass {
+	return []ActivityClass{
+		ActivityClassNonWear,
+		ActivityClassRest,
+		ActivityClassInactive,
+		ActivityClassLow,
+		ActivityClassMedium,
+		ActivityClassHigh,
+	}
+}
+
+func (c ActivityClass) String() string {
+	switch c {
+	case ActivityClassNonWear:
+		return "non_wear"
+	case ActivityClassRest:
+		return "rest"
+	case ActivityClassInactive:
+		return "inactive"
+	case ActivityClassLow:
+		return "low"
+	case ActivityClassMedium:
+		return "medium"
+	case ActivityClassHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("ActivityClass(%d)", int(c))
+	}
+}
+
+// ActivitySegment is a run of consecutive 5 minute periods in the same activity class.  End is exclusive.
+type ActivitySegment struct {
+	Start time.Time
+	End   time.Time
+	Class ActivityClass
+}
+
+// Duration returns the length of the segment.
+func (s ActivitySegment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// ActivityClassMismatch reports a class whose decoded duration differs from the duration reported on the
+// DailyActivity.
+type ActivityClassMismatch struct {
+	Class    ActivityClass
+	Decoded  time.Duration
+	Reported time.Duration
+}
+
+// ActivityClasses is a time ordered list of activity class segments.
+type ActivityClasses []ActivitySegment
+
+// DecodeActivityClasses decodes a Class5Min string into segments, the first starting at start.  Consecutive periods
+// in the same class are merged into one segment.
+func DecodeActivityClasses(classes string, start time.Time) (ActivityClasses, error) {
+	var segments ActivityClasses
+	for i, digit := range classes {
+		class := ActivityClass(digit - '0')
+		if class < ActivityClassNonWear || class > ActivityClassHigh {
+			return nil, fmt.Errorf("invalid activity class %q at index %d", digit, i)
+		}
+
+		periodStart := start.Add(time.Duration(i) * ActivityClassInterval)
+		periodEnd := periodStart.Add(ActivityClassInterval)
+		if last := len(segments) - 1; last >= 0 && segments[last].Class == class {
+			segments[last].End = periodEnd
+			continue
+		}
+		segments = append(segments, ActivitySegment{Start: periodStart, End: periodEnd, Class: class})
+	}
+	return segments, nil
+}
+
+// ActivityClasses decodes Class5Min anchored at Timestamp, the start of the activity day in the user's time zone.
+func (d DailyActivity) ActivityClasses() (ActivityClasses, error) {
+	return DecodeActivityClasses(d.Class5Min, d.Timestamp)
+}
+
+// Totals returns the total time spent in each class.
+func (a ActivityClasses) Totals() map[ActivityClass]time.Duration {
+	totals := make(map[ActivityClass]time.Duration)
+	for _, segment := range a {
+		totals[segment.Class] += segment.Duration()
+	}
+	return totals
+}
+
+// SedentaryBouts returns the inactive segments lasting at least minimum.
+func (a ActivityClasses) SedentaryBouts(minimum time.Duration) []ActivitySegment {
+	var bouts []ActivitySegment
+	for _, segment := range a {
+		if segment.Class == ActivityClassInactive && segment.Duration() >= minimum {
+			bouts = append(bouts, segment)
+		}
+	}
+	return bouts
+}
+
+// CheckDurations compares the decoded class totals with NonWearTime, RestingTime, SedentaryTime, LowActivityTime,
+// MediumActivityTime and HighActivityTime of d, returning the classes which differ by more than tolerance.  The
+// reported times are computed by the API at a finer resolution than Class5Min, so some difference is expected.
+func (a ActivityClasses) CheckDurations(d DailyActivity, tolerance time.Duration) []ActivityClassMismatch {
+	reported := map[ActivityClass]int{
+		ActivityClassNonWear:  d.NonWearTime,
+		ActivityClassRest:     d.RestingTime,
+		ActivityClassInactive: d.SedentaryTime,
+		ActivityClassLow:      d.LowActivityTime,
+		ActivityClassMedium:   d.MediumActivityTime,
+		ActivityClassHigh:     d.HighActivityTime,
+	}
+
+	totals := a.Totals()
+	var mismatches []ActivityClassMismatch
+	for _, class := range ActivityClassValues() {
+		expected := time.Duration(reported[class]) * time.Second
+		difference := totals[class] - expected
+		if difference > tolerance || difference < -tolerance {
+			mismatches = append(mismatches, ActivityClassMismatch{Class: class, Decoded: totals[class], Reported: expected})
+		}
+	}
+	return mismatches
+}
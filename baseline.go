@@ -0,0 +1,190 @@
+// This file contains rolling personal baselines for biomarkers such as resting heart rate and HRV.
+//
+// The Oura app compares each day to the user's baseline, which the API does not expose.  A baseline is the median of
+// the preceding days within a window, with the median absolute deviation as its spread, so single outlying days do
+// not move it.  Missing days simply leave fewer values in the window.
+
+package go_oura
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// madScale scales the median absolute deviation to the standard deviation of normally distributed values.
+const madScale = 1.4826
+
+// meanAbsoluteDeviationScale scales the mean absolute deviation to the standard deviation of normally distributed
+// values.  It is used when more than half the values equal the median, leaving the median absolute deviation zero.
+const meanAbsoluteDeviationScale = 1.2533
+
+// Biomarker is a daily value tracked against a baseline.
+type Biomarker string
+
+const (
+	// BiomarkerRestingHeartRate is Sleep.LowestHeartRate of the main sleep.
+	BiomarkerRestingHeartRate Biomarker = "resting_heart_rate"
+	// BiomarkerHrv is Sleep.AverageHrv of the main sleep.
+	BiomarkerHrv Biomarker = "hrv"
+	// BiomarkerBreathingRate is Sleep.AverageBreath of the main sleep.
+	BiomarkerBreathingRate Biomarker = "breathing_rate"
+	// BiomarkerTemperatureDeviation is DailyReadiness.TemperatureDeviation.
+	BiomarkerTemperatureDeviation Biomarker = "temperature_deviation"
+	// BiomarkerReadinessScore is DailyReadiness.Score.
+	BiomarkerReadinessScore Biomarker = "readiness_score"
+	// BiomarkerSleepScore is DailySleep.Score.
+	BiomarkerSleepScore Biomarker = "sleep_score"
+)
+
+// Biomarkers returns every Biomarker.
+func Biomarkers() []Biomarker {
+	return []Biomarker{
+		BiomarkerRestingHeartRate,
+		BiomarkerHrv,
+		BiomarkerBreathingRate,
+		BiomarkerTemperatureDeviation,
+		BiomarkerReadinessScore,
+		BiomarkerSleepScore,
+	}
+}
+
+// DailyValue is the value of a biomarker for a day.
+type DailyValue struct {
+	Day   Date
+	Value float64
+}
+
+// BaselineDeviation is a day's value compared with the baseline of the days before it.  Ok is false, and Median,
+// Spread and ZScore are zero, when the window holds fewer than MinDays values or they do not vary.
+type BaselineDeviation struct {
+	Day    Date
+	Value  float64
+	Median float64
+	Spread float64
+	ZScore float64
+	Days   int
+	Ok     bool
+}
+
+// BaselineCalculator computes rolling baselines over Window days, requiring MinDays values in the window.
+type BaselineCalculator struct {
+	Window  int
+	MinDays int
+}
+
+// NewBaselineCalculator returns a BaselineCalculator over window days requiring half of them to have a value.
+func NewBaselineCalculator(window int) *BaselineCalculator {
+	return &BaselineCalculator{Window: window, MinDays: max(1, window/2)}
+}
+
+// BiomarkerValues returns the daily values of every Biomarker found in nights.  Sleep values and scores of zero are
+// missing readings and are left out, as are temperature deviations the API reported as null.
+func BiomarkerValues(nights []Night) map[Biomarker][]DailyValue {
+	values := make(map[Biomarker][]DailyValue)
+	add := func(biomarker Biomarker, day Date, value float64) {
+		values[biomarker] = append(values[biomarker], DailyValue{Day: day, Value: value})
+	}
+
+	for _, night := range nights {
+		if sleep := night.MainSleep; sleep != nil {
+			if sleep.LowestHeartRate > 0 {
+				add(BiomarkerRestingHeartRate, night.Day, float64(sleep.LowestHeartRate))
+			}
+			if sleep.AverageHrv > 0 {
+				add(BiomarkerHrv, night.Day, float64(sleep.AverageHrv))
+			}
+			if sleep.AverageBreath > 0 {
+				add(BiomarkerBreathingRate, night.Day, sleep.AverageBreath)
+			}
+		}
+		if readiness := night.DailyReadiness; readiness != nil {
+			if !readiness.TemperatureDeviationMissing {
+				add(BiomarkerTemperatureDeviation, night.Day, readiness.TemperatureDeviation)
+			}
+			if readiness.Score > 0 {
+				add(BiomarkerReadinessScore, night.Day, float64(readiness.Score))
+			}
+		}
+		if night.DailySleep != nil && night.DailySleep.Score > 0 {
+			add(BiomarkerSleepScore, night.Day, float64(night.DailySleep.Score))
+		}
+	}
+
+	return values
+}
+
+// Biomarkers returns the deviations of every Biomarker found in nights.
+func (b *BaselineCalculator) Biomarkers(nights []Night) map[Biomarker][]BaselineDeviation {
+	deviations := make(map[Biomarker][]BaselineDeviation)
+	for biomarker, values := range BiomarkerValues(nights) {
+		deviations[biomarker] = b.Deviations(values)
+	}
+	return deviations
+}
+
+// Deviations returns, in day order, each of values compared with the baseline of the values in the Window days
+// before it.
+func (b *BaselineCalculator) Deviations(values []DailyValue) []BaselineDeviation {
+	sorted := slices.Clone(values)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Day.Before(sorted[j].Day.Time) })
+
+	deviations := make([]BaselineDeviation, len(sorted))
+	first := 0
+	for i, value := range sorted {
+		windowStart := value.Day.AddDate(0, 0, -b.Window)
+		for first < i && sorted[first].Day.Before(windowStart) {
+			first++
+		}
+
+		var window []float64
+		for _, previous := range sorted[first:i] {
+			if previous.Day.Before(value.Day.Time) {
+				window = append(window, previous.Value)
+			}
+		}
+
+		deviation := BaselineDeviation{Day: value.Day, Value: value.Value, Days: len(window)}
+		if len(window) >= b.MinDays && len(window) > 0 {
+			median, spread := robustSpread(window)
+			if spread > 0 {
+				deviation.Median = median
+				deviation.Spread = spread
+				deviation.ZScore = (value.Value - median) / spread
+				deviation.Ok = true
+			}
+		}
+		deviations[i] = deviation
+	}
+
+	return deviations
+}
+
+// robustSpread returns the median of values and the scaled median absolute deviation, or the scaled mean absolute
+// deviation when the median absolute deviation is zero.
+func robustSpread(values []float64) (float64, float64) {
+	center := median(values)
+
+	deviations := make([]float64, len(values))
+	var total float64
+	for i, value := range values {
+		deviations[i] = math.Abs(value - center)
+		total += deviations[i]
+	}
+
+	if mad := median(deviations); mad > 0 {
+		return center, madScale * mad
+	}
+	return center, meanAbsoluteDeviationScale * total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
@@ -10,26 +10,70 @@ import (
 
 // IntervalItems is a common data type used by different Oura Ring types.  It stores an interval, a timestamp, and
 // then 0 or more items recorded during that interval.
+//
+// The API reports missing items as null.  They are decoded as 0 in Items and marked true in Gaps, which is nil when
+// the series has no missing items, so a real reading of 0 is kept apart from a missing one.
 type IntervalItems struct {
 	Interval  float64   `json:"interval"`
 	Items     []float64 `json:"items"`
 	Timestamp time.Time `json:"timestamp"`
+	Gaps      []bool    `json:"-"`
 }
 
-type intervalItemsBase IntervalItems
+// intervalItemsJSON is the JSON form of IntervalItems, with missing items as null.
+type intervalItemsJSON struct {
+	Interval  float64    `json:"interval"`
+	Items     []*float64 `json:"items"`
+	Timestamp time.Time  `json:"timestamp"`
+}
 
 func (ii *IntervalItems) UnmarshalJSON(data []byte) error {
 	if err := checkJSONFields(reflect.TypeOf(*ii), data); err != nil {
 		return err
 	}
+	return ii.decodeJSON(data)
+}
 
-	var documentBase intervalItemsBase
-	err := json.Unmarshal(data, &documentBase)
+// MarshalJSON writes gaps as null so they survive a round trip.
+func (ii IntervalItems) MarshalJSON() ([]byte, error) {
+	document := intervalItemsJSON{Interval: ii.Interval, Timestamp: ii.Timestamp}
+	if ii.Items != nil {
+		document.Items = make([]*float64, len(ii.Items))
+		for i := range ii.Items {
+			if !ii.IsGap(i) {
+				document.Items[i] = &ii.Items[i]
+			}
+		}
+	}
+	return json.Marshal(document)
+}
+
+// IsGap reports whether the i-th item was missing.
+func (ii IntervalItems) IsGap(i int) bool {
+	return i < len(ii.Gaps) && ii.Gaps[i]
+}
+
+func (ii *IntervalItems) decodeJSON(data []byte) error {
+	var document intervalItemsJSON
+	err := json.Unmarshal(data, &document)
 	if err != nil {
 		return err
 	}
 
-	*ii = IntervalItems(documentBase)
+	*ii = IntervalItems{Interval: document.Interval, Timestamp: document.Timestamp}
+	if document.Items != nil {
+		ii.Items = make([]float64, len(document.Items))
+	}
+	for i, item := range document.Items {
+		if item != nil {
+			ii.Items[i] = *item
+			continue
+		}
+		if ii.Gaps == nil {
+			ii.Gaps = make([]bool, len(document.Items))
+		}
+		ii.Gaps[i] = true
+	}
 	return nil
 }
 
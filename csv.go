@@ -0,0 +1,534 @@
+// This file contains a CSV encoder which flattens the Oura Ring types into rows for use in spreadsheets.
+//
+// Column names follow the JSON field names of each type.  Nested structures are flattened with their parent
+// field name as a prefix, for example the Contributors of a DailyReadiness become contributors_activity_balance,
+// contributors_body_temperature and so on.  Lists such as RestMode.Episodes are joined into a single cell.
+
+package go_oura
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions controls how a CSVEncoder writes rows.
+type CSVOptions struct {
+	// Columns limits the output to the named columns, in the given order.  When empty every column is written.
+	Columns []string
+	// Location is the time zone timestamps are converted to.  When nil timestamps keep the offset the API returned.
+	Location *time.Location
+	// Long switches types which carry IntervalItems series (Sleep, Session and DailyActivity) to a long format
+	// with one row per sample.  Long rows contain the id and day of the document followed by the series,
+	// timestamp and value columns.
+	Long bool
+}
+
+// CSVEncoder writes Oura Ring types as CSV with a header row.
+type CSVEncoder struct {
+	writer  *csv.Writer
+	options CSVOptions
+}
+
+// NewCSVEncoder returns a CSVEncoder writing to w using the given options.
+func NewCSVEncoder(w io.Writer, options CSVOptions) *CSVEncoder {
+	return &CSVEncoder{
+		writer:  csv.NewWriter(w),
+		options: options,
+	}
+}
+
+type csvColumn[T any] struct {
+	name  string
+	value func(item T, loc *time.Location) string
+}
+
+type csvSeries[T any] struct {
+	name  string
+	items func(item T) IntervalItems
+}
+
+// EncodeDailyActivities writes one row per DailyActivity.  Contributors are written as contributors_* columns.
+// In long format the met series is expanded into one row per sample.
+func (e *CSVEncoder) EncodeDailyActivities(items []DailyActivity) error {
+	if e.options.Long {
+		return writeCSVLong(e, dailyActivityCSVKeys, dailyActivityCSVSeries, items)
+	}
+	return writeCSV(e, dailyActivityCSVColumns, items)
+}
+
+// EncodeDailyReadinesses writes one row per DailyReadiness.  Contributors are written as contributors_* columns.
+func (e *CSVEncoder) EncodeDailyReadinesses(items []DailyReadiness) error {
+	return writeCSV(e, dailyReadinessCSVColumns, items)
+}
+
+// EncodeDailySleeps writes one row per DailySleep.  Contributors are written as contributors_* columns.
+func (e *CSVEncoder) EncodeDailySleeps(items []DailySleep) error {
+	return writeCSV(e, dailySleepCSVColumns, items)
+}
+
+// EncodeDailySpo2Readings writes one row per DailySpo2Reading.  The percentage is written as spo2_percentage_average.
+func (e *CSVEncoder) EncodeDailySpo2Readings(items []DailySpo2Reading) error {
+	return writeCSV(e, dailySpo2CSVColumns, items)
+}
+
+// EncodeDailyStresses writes one row per DailyStress.
+func (e *CSVEncoder) EncodeDailyStresses(items []DailyStress) error {
+	return writeCSV(e, dailyStressCSVColumns, items)
+}
+
+// EncodeEnhancedTags writes one row per EnhancedTag.  Missing times and days are written as empty cells.
+func (e *CSVEncoder) EncodeEnhancedTags(items []EnhancedTag) error {
+	return writeCSV(e, enhancedTagCSVColumns, items)
+}
+
+// EncodeHeartRates writes one row per HeartRate.
+func (e *CSVEncoder) EncodeHeartRates(items []HeartRate) error {
+	return writeCSV(e, heartRateCSVColumns, items)
+}
+
+// EncodePersonalInfo writes one row per PersonalInfo.
+func (e *CSVEncoder) EncodePersonalInfo(items []PersonalInfo) error {
+	return writeCSV(e, personalInfoCSVColumns, items)
+}
+
+// EncodeRestModes writes one row per RestMode.  Episodes are written as episode_count and episodes, the latter
+// holding each episode as timestamp=tag|tag with episodes separated by semicolons.
+func (e *CSVEncoder) EncodeRestModes(items []RestMode) error {
+	return writeCSV(e, restModeCSVColumns, items)
+}
+
+// EncodeRingConfigurations writes one row per RingConfiguration.
+func (e *CSVEncoder) EncodeRingConfigurations(items []RingConfiguration) error {
+	return writeCSV(e, ringConfigurationCSVColumns, items)
+}
+
+// EncodeSessions writes one row per Session.  In long format the heart_rate, heart_rate_variability and
+// motion_count series are expanded into one row per sample.
+func (e *CSVEncoder) EncodeSessions(items []Session) error {
+	if e.options.Long {
+		return writeCSVLong(e, sessionCSVKeys, sessionCSVSeries, items)
+	}
+	return writeCSV(e, sessionCSVColumns, items)
+}
+
+// EncodeSleeps writes one row per Sleep.  The readiness block is written as readiness_* columns and its
+// contributors as readiness_contributors_* columns.  In long format the heart_rate and hrv series are expanded
+// into one row per sample.
+func (e *CSVEncoder) EncodeSleeps(items []Sleep) error {
+	if e.options.Long {
+		return writeCSVLong(e, sleepCSVKeys, sleepCSVSeries, items)
+	}
+	return writeCSV(e, sleepCSVColumns, items)
+}
+
+// EncodeSleepTimes writes one row per SleepTime.  The optimal bedtime is written as optimal_bedtime_* columns
+// which are empty when no optimal bedtime was recommended.
+func (e *CSVEncoder) EncodeSleepTimes(items []SleepTime) error {
+	return writeCSV(e, sleepTimeCSVColumns, items)
+}
+
+// EncodeWorkouts writes one row per Workout.
+func (e *CSVEncoder) EncodeWorkouts(items []Workout) error {
+	return writeCSV(e, workoutCSVColumns, items)
+}
+
+func writeCSV[T any](e *CSVEncoder, columns []csvColumn[T], items []T) error {
+	selected, err := selectCSVColumns(columns, e.options.Columns)
+	if err != nil {
+		return err
+	}
+
+	header := make([]string, len(selected))
+	for i, column := range selected {
+		header[i] = column.name
+	}
+	if err := e.writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header with error: %v", err)
+	}
+
+	for _, item := range items {
+		row := make([]string, len(selected))
+		for i, column := range selected {
+			row[i] = column.value(item, e.options.Location)
+		}
+		if err := e.writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write csv row with error: %v", err)
+		}
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type csvLongRow[T any] struct {
+	item      T
+	series    string
+	timestamp time.Time
+	value     Sample
+}
+
+func writeCSVLong[T any](e *CSVEncoder, keys []csvColumn[T], series []csvSeries[T], items []T) error {
+	columns := make([]csvColumn[csvLongRow[T]], 0, len(keys)+3)
+	for _, key := range keys {
+		key := key
+		columns = append(columns, csvColumn[csvLongRow[T]]{key.name, func(r csvLongRow[T], loc *time.Location) string {
+			return key.value(r.item, loc)
+		}})
+	}
+	columns = append(columns,
+		csvColumn[csvLongRow[T]]{"series", func(r csvLongRow[T], _ *time.Location) string { return r.series }},
+		csvColumn[csvLongRow[T]]{"timestamp", func(r csvLongRow[T], loc *time.Location) string { return csvTime(r.timestamp, loc) }},
+		csvColumn[csvLongRow[T]]{"value", func(r csvLongRow[T], _ *time.Location) string {
+			if !r.value.Valid {
+				return ""
+			}
+			return csvFloat(r.value.Value)
+		}},
+	)
+
+	var rows []csvLongRow[T]
+	for _, item := range items {
+		for _, s := range series {
+			for _, sample := range s.items(item).Samples() {
+				rows = append(rows, csvLongRow[T]{
+					item:      item,
+					series:    s.name,
+					timestamp: sample.Time,
+					value:     sample,
+				})
+			}
+		}
+	}
+
+	return writeCSV(e, columns, rows)
+}
+
+func selectCSVColumns[T any](columns []csvColumn[T], names []string) ([]csvColumn[T], error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	byName := make(map[string]csvColumn[T], len(columns))
+	for _, column := range columns {
+		byName[column.name] = column
+	}
+
+	selected := make([]csvColumn[T], 0, len(names))
+	for _, name := range names {
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown csv column %s", name)
+		}
+		selected = append(selected, column)
+	}
+
+	return selected, nil
+}
+
+func csvInt[N int | int64](n N) string {
+	return strconv.FormatInt(int64(n), 10)
+}
+
+func csvFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func csvFloat32(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+func csvTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvTimePtr(t *time.Time, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	return csvTime(*t, loc)
+}
+
+func csvDay(d Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format("2006-01-02")
+}
+
+func csvDayPtr(d *Date) string {
+	if d == nil {
+		return ""
+	}
+	return csvDay(*d)
+}
+
+func csvContributors[T any](prefix string, get func(T) Contributors) []csvColumn[T] {
+	return []csvColumn[T]{
+		{prefix + "activity_balance", func(r T, _ *time.Location) string { return csvInt(get(r).ActivityBalance) }},
+		{prefix + "body_temperature", func(r T, _ *time.Location) string { return csvInt(get(r).BodyTemperature) }},
+		{prefix + "hrv_balance", func(r T, _ *time.Location) string { return csvInt(get(r).HrvBalance) }},
+		{prefix + "previous_day_activity", func(r T, _ *time.Location) string { return csvInt(get(r).PreviousDayActivity) }},
+		{prefix + "previous_night", func(r T, _ *time.Location) string { return csvInt(get(r).PreviousNight) }},
+		{prefix + "recovery_index", func(r T, _ *time.Location) string { return csvInt(get(r).RecoveryIndex) }},
+		{prefix + "resting_heart_rate", func(r T, _ *time.Location) string { return csvInt(get(r).RestingHeartRate) }},
+		{prefix + "sleep_balance", func(r T, _ *time.Location) string { return csvInt(get(r).SleepBalance) }},
+	}
+}
+
+func joinCSVColumns[T any](groups ...[]csvColumn[T]) []csvColumn[T] {
+	var columns []csvColumn[T]
+	for _, group := range groups {
+		columns = append(columns, group...)
+	}
+	return columns
+}
+
+var dailyActivityCSVKeys = []csvColumn[DailyActivity]{
+	{"id", func(r DailyActivity, _ *time.Location) string { return r.ID }},
+	{"day", func(r DailyActivity, _ *time.Location) string { return csvDay(r.Day) }},
+}
+
+var dailyActivityCSVSeries = []csvSeries[DailyActivity]{
+	{"met", func(r DailyActivity) IntervalItems { return IntervalItems(r.Met) }},
+}
+
+var dailyActivityCSVColumns = joinCSVColumns(
+	dailyActivityCSVKeys,
+	[]csvColumn[DailyActivity]{
+		{"timestamp", func(r DailyActivity, loc *time.Location) string { return csvTime(r.Timestamp, loc) }},
+		{"score", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Score) }},
+		{"active_calories", func(r DailyActivity, _ *time.Location) string { return csvInt(r.ActiveCalories) }},
+		{"average_met_minutes", func(r DailyActivity, _ *time.Location) string { return csvFloat(r.AverageMetMinutes) }},
+		{"contributors_meet_daily_targets", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.MeetDailyTargets) }},
+		{"contributors_move_every_hour", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.MoveEveryHour) }},
+		{"contributors_recovery_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.RecoveryTime) }},
+		{"contributors_stay_active", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.StayActive) }},
+		{"contributors_training_frequency", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.TrainingFrequency) }},
+		{"contributors_training_volume", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Contributors.TrainingVolume) }},
+		{"equivalent_walking_distance", func(r DailyActivity, _ *time.Location) string { return csvInt(r.EquivalentWalkingDistance) }},
+		{"high_activity_met_minutes", func(r DailyActivity, _ *time.Location) string { return csvInt(r.HighActivityMetMinutes) }},
+		{"high_activity_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.HighActivityTime) }},
+		{"inactivity_alerts", func(r DailyActivity, _ *time.Location) string { return csvInt(r.InactivityAlerts) }},
+		{"low_activity_met_minutes", func(r DailyActivity, _ *time.Location) string { return csvInt(r.LowActivityMetMinutes) }},
+		{"low_activity_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.LowActivityTime) }},
+		{"medium_activity_met_minutes", func(r DailyActivity, _ *time.Location) string { return csvInt(r.MediumActivityMetMinutes) }},
+		{"medium_activity_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.MediumActivityTime) }},
+		{"meters_to_target", func(r DailyActivity, _ *time.Location) string { return csvInt(r.MetersToTarget) }},
+		{"non_wear_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.NonWearTime) }},
+		{"resting_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.RestingTime) }},
+		{"sedentary_met_minutes", func(r DailyActivity, _ *time.Location) string { return csvInt(r.SedentaryMetMinutes) }},
+		{"sedentary_time", func(r DailyActivity, _ *time.Location) string { return csvInt(r.SedentaryTime) }},
+		{"steps", func(r DailyActivity, _ *time.Location) string { return csvInt(r.Steps) }},
+		{"target_calories", func(r DailyActivity, _ *time.Location) string { return csvInt(r.TargetCalories) }},
+		{"target_meters", func(r DailyActivity, _ *time.Location) string { return csvInt(r.TargetMeters) }},
+		{"total_calories", func(r DailyActivity, _ *time.Location) string { return csvInt(r.TotalCalories) }},
+		{"class_5_min", func(r DailyActivity, _ *time.Location) string { return r.Class5Min }},
+	},
+)
+
+var dailyReadinessCSVColumns = joinCSVColumns(
+	[]csvColumn[DailyReadiness]{
+		{"id", func(r DailyReadiness, _ *time.Location) string { return r.Id }},
+		{"day", func(r DailyReadiness, _ *time.Location) string { return csvDay(r.Day) }},
+		{"timestamp", func(r DailyReadiness, loc *time.Location) string { return csvTime(r.Timestamp, loc) }},
+		{"score", func(r DailyReadiness, _ *time.Location) string { return csvInt(r.Score) }},
+		{"temperature_deviation", func(r DailyReadiness, _ *time.Location) string { return csvFloat(r.TemperatureDeviation) }},
+		{"temperature_trend_deviation", func(r DailyReadiness, _ *time.Location) string { return csvFloat(r.TemperatureTrendDeviation) }},
+	},
+	csvContributors("contributors_", func(r DailyReadiness) Contributors { return Contributors(r.Contributors) }),
+)
+
+var dailySleepCSVColumns = []csvColumn[DailySleep]{
+	{"id", func(r DailySleep, _ *time.Location) string { return r.ID }},
+	{"day", func(r DailySleep, _ *time.Location) string { return csvDay(r.Day) }},
+	{"timestamp", func(r DailySleep, loc *time.Location) string { return csvTime(r.Timestamp, loc) }},
+	{"score", func(r DailySleep, _ *time.Location) string { return csvInt(r.Score) }},
+	{"contributors_deep_sleep", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.DeepSleep) }},
+	{"contributors_efficiency", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.Efficiency) }},
+	{"contributors_latency", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.Latency) }},
+	{"contributors_rem_sleep", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.RemSleep) }},
+	{"contributors_restfulness", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.Restfulness) }},
+	{"contributors_timing", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.Timing) }},
+	{"contributors_total_sleep", func(r DailySleep, _ *time.Location) string { return csvInt(r.Contributors.TotalSleep) }},
+}
+
+var dailySpo2CSVColumns = []csvColumn[DailySpo2Reading]{
+	{"id", func(r DailySpo2Reading, _ *time.Location) string { return r.ID }},
+	{"day", func(r DailySpo2Reading, _ *time.Location) string { return csvDay(r.Day) }},
+	{"spo2_percentage_average", func(r DailySpo2Reading, _ *time.Location) string { return csvFloat(r.Percentage.Average) }},
+}
+
+var dailyStressCSVColumns = []csvColumn[DailyStress]{
+	{"id", func(r DailyStress, _ *time.Location) string { return r.ID }},
+	{"day", func(r DailyStress, _ *time.Location) string { return csvDay(r.Day) }},
+	{"stress_high", func(r DailyStress, _ *time.Location) string { return csvInt(r.StressHigh) }},
+	{"recovery_high", func(r DailyStress, _ *time.Location) string { return csvInt(r.RecoveryHigh) }},
+	{"day_summary", func(r DailyStress, _ *time.Location) string { return string(r.DaySummary) }},
+}
+
+var enhancedTagCSVColumns = []csvColumn[EnhancedTag]{
+	{"id", func(r EnhancedTag, _ *time.Location) string { return r.ID }},
+	{"tag_type_code", func(r EnhancedTag, _ *time.Location) string { return r.TagTypeCode }},
+	{"start_time", func(r EnhancedTag, loc *time.Location) string { return csvTimePtr(r.StartTime, loc) }},
+	{"end_time", func(r EnhancedTag, loc *time.Location) string { return csvTimePtr(r.EndTime, loc) }},
+	{"start_day", func(r EnhancedTag, _ *time.Location) string { return csvDayPtr(r.StartDay) }},
+	{"end_day", func(r EnhancedTag, _ *time.Location) string { return csvDayPtr(r.EndDay) }},
+	{"comment", func(r EnhancedTag, _ *time.Location) string { return r.Comment }},
+}
+
+var heartRateCSVColumns = []csvColumn[HeartRate]{
+	{"timestamp", func(r HeartRate, loc *time.Location) string { return csvTime(r.Timestamp, loc) }},
+	{"bpm", func(r HeartRate, _ *time.Location) string { return csvInt(r.Bpm) }},
+	{"source", func(r HeartRate, _ *time.Location) string { return string(r.Source) }},
+}
+
+var personalInfoCSVColumns = []csvColumn[PersonalInfo]{
+	{"id", func(r PersonalInfo, _ *time.Location) string { return r.ID }},
+	{"age", func(r PersonalInfo, _ *time.Location) string { return csvInt(r.Age) }},
+	{"height", func(r PersonalInfo, _ *time.Location) string { return csvFloat32(r.Height) }},
+	{"weight", func(r PersonalInfo, _ *time.Location) string { return csvFloat32(r.Weight) }},
+	{"biological_sex", func(r PersonalInfo, _ *time.Location) string { return r.Sex }},
+	{"email", func(r PersonalInfo, _ *time.Location) string { return r.Email }},
+}
+
+var restModeCSVColumns = []csvColumn[RestMode]{
+	{"id", func(r RestMode, _ *time.Location) string { return r.ID }},
+	{"start_day", func(r RestMode, _ *time.Location) string { return csvDay(r.StartDay) }},
+	{"end_day", func(r RestMode, _ *time.Location) string { return csvDay(r.EndDay) }},
+	{"start_time", func(r RestMode, loc *time.Location) string { return csvTime(r.StartTime, loc) }},
+	{"end_time", func(r RestMode, loc *time.Location) string { return csvTime(r.EndTime, loc) }},
+	{"episode_count", func(r RestMode, _ *time.Location) string { return csvInt(len(r.Episodes)) }},
+	{"episodes", func(r RestMode, loc *time.Location) string {
+		episodes := make([]string, len(r.Episodes))
+		for i, episode := range r.Episodes {
+			episodes[i] = csvTime(episode.Timestamp, loc) + "=" + strings.Join(episode.Tags, "|")
+		}
+		return strings.Join(episodes, ";")
+	}},
+}
+
+var ringConfigurationCSVColumns = []csvColumn[RingConfiguration]{
+	{"id", func(r RingConfiguration, _ *time.Location) string { return r.ID }},
+	{"color", func(r RingConfiguration, _ *time.Location) string { return r.Color }},
+	{"design", func(r RingConfiguration, _ *time.Location) string { return r.Design }},
+	{"firmware_version", func(r RingConfiguration, _ *time.Location) string { return r.FirmwareVersion }},
+	{"hardware_type", func(r RingConfiguration, _ *time.Location) string { return r.HardwareType }},
+	{"set_up_at", func(r RingConfiguration, loc *time.Location) string { return csvTimePtr(r.SetUpAt, loc) }},
+	{"size", func(r RingConfiguration, _ *time.Location) string { return csvInt(r.Size) }},
+}
+
+var sessionCSVKeys = []csvColumn[Session]{
+	{"id", func(r Session, _ *time.Location) string { return r.ID }},
+	{"day", func(r Session, _ *time.Location) string { return csvDay(r.Day) }},
+}
+
+var sessionCSVSeries = []csvSeries[Session]{
+	{"heart_rate", func(r Session) IntervalItems { return IntervalItems(r.HeartRateData) }},
+	{"heart_rate_variability", func(r Session) IntervalItems { return IntervalItems(r.HeartRateVariabilityData) }},
+	{"motion_count", func(r Session) IntervalItems { return IntervalItems(r.MotionCountData) }},
+}
+
+var sessionCSVColumns = joinCSVColumns(
+	sessionCSVKeys,
+	[]csvColumn[Session]{
+		{"start_datetime", func(r Session, loc *time.Location) string { return csvTime(r.StartDatetime, loc) }},
+		{"end_datetime", func(r Session, loc *time.Location) string { return csvTime(r.EndDatetime, loc) }},
+		{"type", func(r Session, _ *time.Location) string { return string(r.Type) }},
+		{"mood", func(r Session, _ *time.Location) string { return string(r.Mood) }},
+	},
+)
+
+var sleepCSVKeys = []csvColumn[Sleep]{
+	{"id", func(r Sleep, _ *time.Location) string { return r.ID }},
+	{"day", func(r Sleep, _ *time.Location) string { return csvDay(r.Day) }},
+}
+
+var sleepCSVSeries = []csvSeries[Sleep]{
+	{"heart_rate", func(r Sleep) IntervalItems { return r.HeartRate }},
+	{"hrv", func(r Sleep) IntervalItems { return r.Hrv }},
+}
+
+var sleepCSVColumns = joinCSVColumns(
+	sleepCSVKeys,
+	[]csvColumn[Sleep]{
+		{"type", func(r Sleep, _ *time.Location) string { return string(r.Type) }},
+		{"period", func(r Sleep, _ *time.Location) string { return csvInt(r.Period) }},
+		{"bedtime_start", func(r Sleep, loc *time.Location) string { return csvTime(r.BedtimeStart, loc) }},
+		{"bedtime_end", func(r Sleep, loc *time.Location) string { return csvTime(r.BedtimeEnd, loc) }},
+		{"average_breath", func(r Sleep, _ *time.Location) string { return csvFloat(r.AverageBreath) }},
+		{"average_heart_rate", func(r Sleep, _ *time.Location) string { return csvFloat(r.AverageHeartRate) }},
+		{"average_hrv", func(r Sleep, _ *time.Location) string { return csvInt(r.AverageHrv) }},
+		{"awake_time", func(r Sleep, _ *time.Location) string { return csvInt(r.AwakeTime) }},
+		{"deep_sleep_duration", func(r Sleep, _ *time.Location) string { return csvInt(r.DeepSleepDuration) }},
+		{"efficiency", func(r Sleep, _ *time.Location) string { return csvInt(r.Efficiency) }},
+		{"latency", func(r Sleep, _ *time.Location) string { return csvInt(r.Latency) }},
+		{"light_sleep_duration", func(r Sleep, _ *time.Location) string { return csvInt(r.LightSleepDuration) }},
+		{"low_battery_alert", func(r Sleep, _ *time.Location) string { return strconv.FormatBool(r.LowBatteryAlert) }},
+		{"lowest_heart_rate", func(r Sleep, _ *time.Location) string { return csvInt(r.LowestHeartRate) }},
+		{"readiness_score", func(r Sleep, _ *time.Location) string { return csvInt(r.Readiness.Score) }},
+		{"readiness_temperature_deviation", func(r Sleep, _ *time.Location) string { return csvFloat(r.Readiness.TemperatureDeviation) }},
+		{"readiness_temperature_trend_deviation", func(r Sleep, _ *time.Location) string {
+			return csvFloat(r.Readiness.TemperatureTrendDeviation)
+		}},
+	},
+	csvContributors("readiness_contributors_", func(r Sleep) Contributors { return r.Readiness.Contributors }),
+	[]csvColumn[Sleep]{
+		{"readiness_score_delta", func(r Sleep, _ *time.Location) string { return csvInt(r.ReadinessScoreDelta) }},
+		{"rem_sleep_duration", func(r Sleep, _ *time.Location) string { return csvInt(r.RemSleepDuration) }},
+		{"restless_periods", func(r Sleep, _ *time.Location) string { return csvInt(r.RestlessPeriods) }},
+		{"sleep_score_delta", func(r Sleep, _ *time.Location) string { return csvInt(r.SleepScoreDelta) }},
+		{"sleep_algorithm_version", func(r Sleep, _ *time.Location) string { return r.SleepAlgorithmVersion }},
+		{"time_in_bed", func(r Sleep, _ *time.Location) string { return csvInt(r.TimeInBed) }},
+		{"total_sleep_duration", func(r Sleep, _ *time.Location) string { return csvInt(r.TotalSleepDuration) }},
+		{"sleep_phase_5_min", func(r Sleep, _ *time.Location) string { return r.SleepPhase5Min }},
+		{"movement_30_sec", func(r Sleep, _ *time.Location) string { return r.Movement30Sec }},
+	},
+)
+
+var sleepTimeCSVColumns = []csvColumn[SleepTime]{
+	{"id", func(r SleepTime, _ *time.Location) string { return r.ID }},
+	{"day", func(r SleepTime, _ *time.Location) string { return csvDay(r.Day) }},
+	{"optimal_bedtime_day_tz", func(r SleepTime, _ *time.Location) string {
+		if r.OptimalBedtime == nil {
+			return ""
+		}
+		return csvInt(r.OptimalBedtime.DayTz)
+	}},
+	{"optimal_bedtime_start_offset", func(r SleepTime, _ *time.Location) string {
+		if r.OptimalBedtime == nil {
+			return ""
+		}
+		return csvInt(r.OptimalBedtime.StartOffset)
+	}},
+	{"optimal_bedtime_end_offset", func(r SleepTime, _ *time.Location) string {
+		if r.OptimalBedtime == nil {
+			return ""
+		}
+		return csvInt(r.OptimalBedtime.EndOffset)
+	}},
+	{"recommendation", func(r SleepTime, _ *time.Location) string { return string(r.Recommendation) }},
+	{"status", func(r SleepTime, _ *time.Location) string { return string(r.Status) }},
+}
+
+var workoutCSVColumns = []csvColumn[Workout]{
+	{"id", func(r Workout, _ *time.Location) string { return r.Id }},
+	{"day", func(r Workout, _ *time.Location) string { return csvDay(r.Day) }},
+	{"activity", func(r Workout, _ *time.Location) string { return string(r.Activity) }},
+	{"label", func(r Workout, _ *time.Location) string { return r.Label }},
+	{"intensity", func(r Workout, _ *time.Location) string { return string(r.Intensity) }},
+	{"source", func(r Workout, _ *time.Location) string { return string(r.Source) }},
+	{"start_datetime", func(r Workout, loc *time.Location) string { return csvTime(r.StartDatetime, loc) }},
+	{"end_datetime", func(r Workout, loc *time.Location) string { return csvTime(r.EndDatetime, loc) }},
+	{"calories", func(r Workout, _ *time.Location) string { return csvFloat(r.Calories) }},
+	{"distance", func(r Workout, _ *time.Location) string { return csvFloat(r.Distance) }},
+}
@@ -64,6 +64,19 @@ type Contributor struct {
 // Met is a Metabolic Equivalent of Task Minutes.
 type Met IntervalItems
 
+// UnmarshalJSON decodes Met as IntervalItems, recording missing items in Gaps.
+func (m *Met) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return (*IntervalItems)(m).decodeJSON(data)
+}
+
+// MarshalJSON encodes Met as IntervalItems, writing gaps as null.
+func (m Met) MarshalJSON() ([]byte, error) {
+	return IntervalItems(m).MarshalJSON()
+}
+
 type dailyActivityBase DailyActivity
 type dailyActivitiesBase DailyActivities
 
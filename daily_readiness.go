@@ -25,17 +25,27 @@ type DailyReadinesses struct {
 type ReadinessContributors Contributors
 
 // DailyReadiness describes your daily readiness
+//
+// The API reports a temperature deviation it could not measure as null.  It is decoded as 0 with
+// TemperatureDeviationMissing set, so a real deviation of 0 is kept apart from a missing one.
 type DailyReadiness struct {
-	Id                        string                `json:"id"`
-	Contributors              ReadinessContributors `json:"contributors"`
-	Day                       Date                  `json:"day"`
-	Score                     int                   `json:"score"`
-	TemperatureDeviation      float64               `json:"temperature_deviation"`
-	TemperatureTrendDeviation float64               `json:"temperature_trend_deviation"`
-	Timestamp                 time.Time             `json:"timestamp"`
+	Id                          string                `json:"id"`
+	Contributors                ReadinessContributors `json:"contributors"`
+	Day                         Date                  `json:"day"`
+	Score                       int                   `json:"score"`
+	TemperatureDeviation        float64               `json:"temperature_deviation"`
+	TemperatureDeviationMissing bool                  `json:"-"`
+	TemperatureTrendDeviation   float64               `json:"temperature_trend_deviation"`
+	Timestamp                   time.Time             `json:"timestamp"`
 }
 
 type dailyReadinessDocumentBase DailyReadiness
+
+// dailyReadinessJSON is the JSON form of DailyReadiness, with a missing temperature deviation as null.
+type dailyReadinessJSON struct {
+	dailyReadinessDocumentBase
+	TemperatureDeviation *float64 `json:"temperature_deviation"`
+}
 type dailyReadinessDocumentsBase DailyReadinesses
 
 // UnmarshalJSON is a helper function to convert daily readinesses JSON from the API to the DailyReadinesses type.
@@ -60,16 +70,30 @@ func (dr *DailyReadiness) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	var documentBase dailyReadinessDocumentBase
-	err := json.Unmarshal(data, &documentBase)
+	var document dailyReadinessJSON
+	err := json.Unmarshal(data, &document)
 	if err != nil {
 		return err
 	}
 
-	*dr = DailyReadiness(documentBase)
+	*dr = DailyReadiness(document.dailyReadinessDocumentBase)
+	if document.TemperatureDeviation != nil {
+		dr.TemperatureDeviation = *document.TemperatureDeviation
+	} else {
+		dr.TemperatureDeviationMissing = true
+	}
 	return nil
 }
 
+// MarshalJSON writes a missing temperature deviation as null so it survives a round trip.
+func (dr DailyReadiness) MarshalJSON() ([]byte, error) {
+	document := dailyReadinessJSON{dailyReadinessDocumentBase: dailyReadinessDocumentBase(dr)}
+	if !dr.TemperatureDeviationMissing {
+		document.TemperatureDeviation = &dr.TemperatureDeviation
+	}
+	return json.Marshal(document)
+}
+
 // GetReadinesses accepts a start & end date and returns a DailyReadinesses object which will contain any DailyReadiness
 // found in the time period.  Optionally the next token can be passed which tells the API to give the next set of
 // activities if the date range returns a large set.
@@ -23,11 +23,11 @@ type DailyStresses struct {
 // DailyStress describes daily stress summary values
 // JSON described at https://cloud.ouraring.com/v2/docs#operation/Single_daily_stress_Document_v2_usercollection_daily_stress__document_id__get
 type DailyStress struct {
-	ID           string `json:"id"`
-	Day          Date   `json:"day"`
-	StressHigh   int64  `json:"stress_high"`
-	RecoveryHigh int64  `json:"recovery_high"`
-	DaySummary   string `json:"day_summary"`
+	ID           string           `json:"id"`
+	Day          Date             `json:"day"`
+	StressHigh   int64            `json:"stress_high"`
+	RecoveryHigh int64            `json:"recovery_high"`
+	DaySummary   StressDaySummary `json:"day_summary"`
 }
 
 type StressBase DailyStress
@@ -28,3 +28,35 @@ func (d *Date) UnmarshalJSON(input []byte) error {
 	d.Time = newTime
 	return nil
 }
+
+// MarshalJSON writes d as YYYY-MM-DD, the format of the API and of UnmarshalJSON, and a zero Date as null.  Without
+// it the embedded time.Time would be written as RFC 3339, which UnmarshalJSON cannot read back.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + d.Format("2006-01-02") + `"`), nil
+}
+
+// DateOf returns the calendar day of t in the location of t.
+func DateOf(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// MidnightIn returns midnight at the start of the calendar day d in loc.  Unlike d.In, which is time.Time.In, the
+// instant changes: a Date holds a calendar day, parsed as UTC midnight, rather than an instant.
+func (d Date) MidnightIn(loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+// StartOfDay returns the first instant of the calendar day d in loc.
+func (d Date) StartOfDay(loc *time.Location) time.Time {
+	return d.MidnightIn(loc)
+}
+
+// EndOfDay returns the first instant of the calendar day after d in loc, the exclusive end of d.  Days are not
+// always 24 hours long when loc observes daylight saving time.
+func (d Date) EndOfDay(loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,358 @@
+// This file contains the shared behaviour of the string coded enum types such as SleepType and WorkoutIntensity.
+//
+// Enum types decode any value the API returns, so values added to the API after this package was written are
+// preserved rather than rejected.  UnknownEnums lists the values of a decoded document which are not one of the known
+// constants, and they are also reported to the process wide handler set with SetUnknownEnumHandler as they are
+// decoded.  WorkoutActivity is open ended, the API has far more activities than there are constants, so its values
+// are never reported.
+
+package go_oura
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+)
+
+// UnknownEnumHandler is called with the name of the enum type, for example "SleepType", and the unrecognized value.
+type UnknownEnumHandler func(typeName string, value string)
+
+var unknownEnumHandler atomic.Pointer[UnknownEnumHandler]
+
+// SetUnknownEnumHandler sets the handler called when an enum field is decoded with a value which is not one of the
+// known constants.  Passing nil removes the handler.  The handler may be called concurrently.
+//
+// The handler is shared by every decode in the process, so it cannot tell which Client or document a value came
+// from.  Use UnknownEnums on the decoded documents to report per document instead.
+func SetUnknownEnumHandler(handler UnknownEnumHandler) {
+	if handler == nil {
+		unknownEnumHandler.Store(nil)
+		return
+	}
+	unknownEnumHandler.Store(&handler)
+}
+
+type enum interface {
+	~string
+	Known() bool
+}
+
+// openEnum is implemented by enum types whose constants only cover part of the values the API returns, so values
+// which are not known are expected and never reported.
+type openEnum interface {
+	openEnded()
+}
+
+// UnknownEnum is an enum value which is not one of the known constants.  Field is the path of the value in the
+// document, for example "Items[2].Type".
+type UnknownEnum struct {
+	Field string
+	Type  string
+	Value string
+}
+
+// UnknownEnums returns the enum values in document, a decoded document or list of documents, which are neither empty
+// nor one of the known constants.  Open ended enums such as WorkoutActivity are left out.
+func UnknownEnums(document any) []UnknownEnum {
+	var unknown []UnknownEnum
+	collectUnknownEnums(reflect.ValueOf(document), "", &unknown)
+	return unknown
+}
+
+func collectUnknownEnums(v reflect.Value, path string, unknown *[]UnknownEnum) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectUnknownEnums(v.Elem(), path, unknown)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			collectUnknownEnums(v.Field(i), fieldPath, unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.String, reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				collectUnknownEnums(v.Index(i), fmt.Sprintf("%s[%d]", path, i), unknown)
+			}
+		}
+	case reflect.String:
+		if value, ok := v.Interface().(interface{ Known() bool }); ok && reportEnum(value, v.String()) {
+			*unknown = append(*unknown, UnknownEnum{Field: path, Type: v.Type().Name(), Value: v.String()})
+		}
+	}
+}
+
+// reportEnum returns whether the enum value with text should be reported as unknown.
+func reportEnum(value interface{ Known() bool }, text string) bool {
+	if _, open := value.(openEnum); open {
+		return false
+	}
+	return text != "" && !value.Known()
+}
+
+func knownEnum[T ~string](value T, values []T) bool {
+	return slices.Contains(values, value)
+}
+
+// unmarshalEnum sets value from text, reporting it when it is neither empty nor known.
+func unmarshalEnum[T enum](value *T, text []byte) {
+	*value = T(text)
+	if !reportEnum(*value, string(text)) {
+		return
+	}
+
+	if handler := unknownEnumHandler.Load(); handler != nil {
+		(*handler)(reflect.TypeOf(*value).Name(), string(*value))
+	}
+}
+
+// SleepType is the type of a Sleep.
+type SleepType string
+
+const (
+	SleepTypeDeleted   SleepType = "deleted"
+	SleepTypeSleep     SleepType = "sleep"
+	SleepTypeLongSleep SleepType = "long_sleep"
+	SleepTypeLateNap   SleepType = "late_nap"
+	SleepTypeRest      SleepType = "rest"
+)
+
+// SleepTypes returns every known SleepType.
+func SleepTypes() []SleepType {
+	return []SleepType{SleepTypeDeleted, SleepTypeSleep, SleepTypeLongSleep, SleepTypeLateNap, SleepTypeRest}
+}
+
+func (t SleepType) Known() bool                      { return knownEnum(t, SleepTypes()) }
+func (t SleepType) String() string                   { return string(t) }
+func (t SleepType) MarshalText() ([]byte, error)     { return []byte(t), nil }
+func (t *SleepType) UnmarshalText(text []byte) error { unmarshalEnum(t, text); return nil }
+
+// WorkoutActivity is the activity of a Workout.  The API supports many activities, only the common ones have
+// constants, so unknown activities are not reported.
+type WorkoutActivity string
+
+const (
+	WorkoutActivityCycling          WorkoutActivity = "cycling"
+	WorkoutActivityDancing          WorkoutActivity = "dancing"
+	WorkoutActivityElliptical       WorkoutActivity = "elliptical"
+	WorkoutActivityHiking           WorkoutActivity = "hiking"
+	WorkoutActivityHousework        WorkoutActivity = "housework"
+	WorkoutActivityOther            WorkoutActivity = "other"
+	WorkoutActivityPilates          WorkoutActivity = "pilates"
+	WorkoutActivityRowing           WorkoutActivity = "rowing"
+	WorkoutActivityRunning          WorkoutActivity = "running"
+	WorkoutActivityStrengthTraining WorkoutActivity = "strength_training"
+	WorkoutActivitySwimming         WorkoutActivity = "swimming"
+	WorkoutActivityTennis           WorkoutActivity = "tennis"
+	WorkoutActivityWalking          WorkoutActivity = "walking"
+	WorkoutActivityYoga             WorkoutActivity = "yoga"
+)
+
+// WorkoutActivities returns every WorkoutActivity with a constant.
+func WorkoutActivities() []WorkoutActivity {
+	return []WorkoutActivity{
+		WorkoutActivityCycling,
+		WorkoutActivityDancing,
+		WorkoutActivityElliptical,
+		WorkoutActivityHiking,
+		WorkoutActivityHousework,
+		WorkoutActivityOther,
+		WorkoutActivityPilates,
+		WorkoutActivityRowing,
+		WorkoutActivityRunning,
+		WorkoutActivityStrengthTraining,
+		WorkoutActivitySwimming,
+		WorkoutActivityTennis,
+		WorkoutActivityWalking,
+		WorkoutActivityYoga,
+	}
+}
+
+func (a WorkoutActivity) openEnded()                       {}
+func (a WorkoutActivity) Known() bool                      { return knownEnum(a, WorkoutActivities()) }
+func (a WorkoutActivity) String() string                   { return string(a) }
+func (a WorkoutActivity) MarshalText() ([]byte, error)     { return []byte(a), nil }
+func (a *WorkoutActivity) UnmarshalText(text []byte) error { unmarshalEnum(a, text); return nil }
+
+// WorkoutIntensity is the intensity of a Workout.
+type WorkoutIntensity string
+
+const (
+	WorkoutIntensityEasy     WorkoutIntensity = "easy"
+	WorkoutIntensityModerate WorkoutIntensity = "moderate"
+	WorkoutIntensityHard     WorkoutIntensity = "hard"
+)
+
+// WorkoutIntensities returns every known WorkoutIntensity.
+func WorkoutIntensities() []WorkoutIntensity {
+	return []WorkoutIntensity{WorkoutIntensityEasy, WorkoutIntensityModerate, WorkoutIntensityHard}
+}
+
+func (i WorkoutIntensity) Known() bool                      { return knownEnum(i, WorkoutIntensities()) }
+func (i WorkoutIntensity) String() string                   { return string(i) }
+func (i WorkoutIntensity) MarshalText() ([]byte, error)     { return []byte(i), nil }
+func (i *WorkoutIntensity) UnmarshalText(text []byte) error { unmarshalEnum(i, text); return nil }
+
+// WorkoutSource describes how a Workout was recorded.
+type WorkoutSource string
+
+const (
+	WorkoutSourceManual           WorkoutSource = "manual"
+	WorkoutSourceAutodetected     WorkoutSource = "autodetected"
+	WorkoutSourceConfirmed        WorkoutSource = "confirmed"
+	WorkoutSourceWorkoutHeartRate WorkoutSource = "workout_heart_rate"
+)
+
+// WorkoutSources returns every known WorkoutSource.
+func WorkoutSources() []WorkoutSource {
+	return []WorkoutSource{WorkoutSourceManual, WorkoutSourceAutodetected, WorkoutSourceConfirmed, WorkoutSourceWorkoutHeartRate}
+}
+
+func (s WorkoutSource) Known() bool                      { return knownEnum(s, WorkoutSources()) }
+func (s WorkoutSource) String() string                   { return string(s) }
+func (s WorkoutSource) MarshalText() ([]byte, error)     { return []byte(s), nil }
+func (s *WorkoutSource) UnmarshalText(text []byte) error { unmarshalEnum(s, text); return nil }
+
+// HeartRateSource describes what the ring was measuring when a HeartRate was recorded.
+type HeartRateSource string
+
+const (
+	HeartRateSourceAwake   HeartRateSource = "awake"
+	HeartRateSourceRest    HeartRateSource = "rest"
+	HeartRateSourceSleep   HeartRateSource = "sleep"
+	HeartRateSourceSession HeartRateSource = "session"
+	HeartRateSourceLive    HeartRateSource = "live"
+	HeartRateSourceWorkout HeartRateSource = "workout"
+)
+
+// HeartRateSources returns every known HeartRateSource.
+func HeartRateSources() []HeartRateSource {
+	return []HeartRateSource{
+		HeartRateSourceAwake,
+		HeartRateSourceRest,
+		HeartRateSourceSleep,
+		HeartRateSourceSession,
+		HeartRateSourceLive,
+		HeartRateSourceWorkout,
+	}
+}
+
+func (s HeartRateSource) Known() bool                      { return knownEnum(s, HeartRateSources()) }
+func (s HeartRateSource) String() string                   { return string(s) }
+func (s HeartRateSource) MarshalText() ([]byte, error)     { return []byte(s), nil }
+func (s *HeartRateSource) UnmarshalText(text []byte) error { unmarshalEnum(s, text); return nil }
+
+// SessionType is the type of a Session.
+type SessionType string
+
+const (
+	SessionTypeBreathing  SessionType = "breathing"
+	SessionTypeMeditation SessionType = "meditation"
+	SessionTypeNap        SessionType = "nap"
+	SessionTypeRelaxation SessionType = "relaxation"
+	SessionTypeRest       SessionType = "rest"
+	SessionTypeBodyStatus SessionType = "body_status"
+)
+
+// SessionTypes returns every known SessionType.
+func SessionTypes() []SessionType {
+	return []SessionType{
+		SessionTypeBreathing,
+		SessionTypeMeditation,
+		SessionTypeNap,
+		SessionTypeRelaxation,
+		SessionTypeRest,
+		SessionTypeBodyStatus,
+	}
+}
+
+func (t SessionType) Known() bool                      { return knownEnum(t, SessionTypes()) }
+func (t SessionType) String() string                   { return string(t) }
+func (t SessionType) MarshalText() ([]byte, error)     { return []byte(t), nil }
+func (t *SessionType) UnmarshalText(text []byte) error { unmarshalEnum(t, text); return nil }
+
+// SessionMood is the mood recorded after a Session.  It is empty when no mood was recorded.
+type SessionMood string
+
+const (
+	SessionMoodBad   SessionMood = "bad"
+	SessionMoodWorse SessionMood = "worse"
+	SessionMoodSame  SessionMood = "same"
+	SessionMoodGood  SessionMood = "good"
+	SessionMoodGreat SessionMood = "great"
+)
+
+// SessionMoods returns every known SessionMood.
+func SessionMoods() []SessionMood {
+	return []SessionMood{SessionMoodBad, SessionMoodWorse, SessionMoodSame, SessionMoodGood, SessionMoodGreat}
+}
+
+func (m SessionMood) Known() bool                      { return knownEnum(m, SessionMoods()) }
+func (m SessionMood) String() string                   { return string(m) }
+func (m SessionMood) MarshalText() ([]byte, error)     { return []byte(m), nil }
+func (m *SessionMood) UnmarshalText(text []byte) error { unmarshalEnum(m, text); return nil }
+
+// StressDaySummary is the summary of a DailyStress.  It is empty when the day has no summary.
+type StressDaySummary string
+
+const (
+	StressDaySummaryRestored  StressDaySummary = "restored"
+	StressDaySummaryNormal    StressDaySummary = "normal"
+	StressDaySummaryStressful StressDaySummary = "stressful"
+)
+
+// StressDaySummaries returns every known StressDaySummary.
+func StressDaySummaries() []StressDaySummary {
+	return []StressDaySummary{StressDaySummaryRestored, StressDaySummaryNormal, StressDaySummaryStressful}
+}
+
+func (s StressDaySummary) Known() bool                      { return knownEnum(s, StressDaySummaries()) }
+func (s StressDaySummary) String() string                   { return string(s) }
+func (s StressDaySummary) MarshalText() ([]byte, error)     { return []byte(s), nil }
+func (s *StressDaySummary) UnmarshalText(text []byte) error { unmarshalEnum(s, text); return nil }
+
+// SleepTimeRecommendations returns every known SleepTimeRecommendation.
+func SleepTimeRecommendations() []SleepTimeRecommendation {
+	return []SleepTimeRecommendation{
+		SleepTimeImproveEfficiency,
+		SleepTimeEarlierBedtime,
+		SleepTimeLaterBedtime,
+		SleepTimeEarlierWakeUpTime,
+		SleepTimeLaterWakeUpTime,
+		SleepTimeFollowOptimalBedtime,
+	}
+}
+
+func (r SleepTimeRecommendation) Known() bool                  { return knownEnum(r, SleepTimeRecommendations()) }
+func (r SleepTimeRecommendation) String() string               { return string(r) }
+func (r SleepTimeRecommendation) MarshalText() ([]byte, error) { return []byte(r), nil }
+func (r *SleepTimeRecommendation) UnmarshalText(text []byte) error {
+	unmarshalEnum(r, text)
+	return nil
+}
+
+// SleepTimeStatuses returns every known SleepTimeStatus.
+func SleepTimeStatuses() []SleepTimeStatus {
+	return []SleepTimeStatus{
+		SleepTimeNotEnoughNights,
+		SleepTimeNotEnoughRecentNights,
+		SleepTimeBadSleepQuality,
+		SleepTimeOnlyRecommendedFound,
+		SleepTimeOptimalFound,
+	}
+}
+
+func (s SleepTimeStatus) Known() bool                      { return knownEnum(s, SleepTimeStatuses()) }
+func (s SleepTimeStatus) String() string                   { return string(s) }
+func (s SleepTimeStatus) MarshalText() ([]byte, error)     { return []byte(s), nil }
+func (s *SleepTimeStatus) UnmarshalText(text []byte) error { unmarshalEnum(s, text); return nil }
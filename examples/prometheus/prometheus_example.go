@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/austinmoody/go_oura"
+)
+
+// Serves the latest Oura Ring scores for Prometheus to scrape at http://localhost:2112/metrics
+func main() {
+	client := go_oura.NewClient(os.Getenv("OURA_ACCESS_TOKEN"))
+
+	// The user label is on every series, so it is configured rather than taken from personal info such as the email.
+	user := os.Getenv("OURA_METRICS_USER")
+	if user == "" {
+		user = "me"
+	}
+
+	exporter := go_oura.NewMetricsExporter(client, user)
+	go func() {
+		_ = exporter.Run(context.Background())
+	}()
+
+	http.Handle("/metrics", exporter)
+
+	fmt.Println("Serving metrics on :2112/metrics")
+	if err := http.ListenAndServe(":2112", nil); err != nil {
+		fmt.Printf("Error serving metrics: %v", err)
+	}
+}
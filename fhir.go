@@ -0,0 +1,261 @@
+// This file contains a converter from Oura Ring data to FHIR R4 Observation resources.
+//
+// Observations are coded with LOINC and carry UCUM units.  Values LOINC has no code for are coded in the go_oura code
+// system, fhirOuraSystem, a canonical URI identifying the codes rather than a page to resolve.  FHIR R4 Observation is
+// described at https://hl7.org/fhir/R4/observation.html
+
+package go_oura
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	fhirLoincSystem      = "http://loinc.org"
+	fhirUcumSystem       = "http://unitsofmeasure.org"
+	fhirCategorySystem   = "http://terminology.hl7.org/CodeSystem/observation-category"
+	fhirOuraSystem       = "https://github.com/austinmoody/go_oura/fhir/CodeSystem/oura"
+	fhirDateTimeLayout   = "2006-01-02T15:04:05-07:00"
+	fhirDateLayout       = "2006-01-02"
+	fhirCategoryVitals   = "vital-signs"
+	fhirCategoryActivity = "activity"
+)
+
+// FHIRBundle is a FHIR R4 Bundle of Observations.
+type FHIRBundle struct {
+	ResourceType string            `json:"resourceType"`
+	Type         string            `json:"type"`
+	Timestamp    string            `json:"timestamp,omitempty"`
+	Entry        []FHIRBundleEntry `json:"entry"`
+}
+
+// FHIRBundleEntry is a single entry of a FHIRBundle.
+type FHIRBundleEntry struct {
+	Resource FHIRObservation `json:"resource"`
+}
+
+// FHIRObservation is a FHIR R4 Observation.  Only the elements go_oura fills in are included.
+type FHIRObservation struct {
+	ResourceType      string                `json:"resourceType"`
+	ID                string                `json:"id,omitempty"`
+	Status            string                `json:"status"`
+	Category          []FHIRCodeableConcept `json:"category,omitempty"`
+	Code              FHIRCodeableConcept   `json:"code"`
+	Subject           *FHIRReference        `json:"subject,omitempty"`
+	EffectiveDateTime string                `json:"effectiveDateTime,omitempty"`
+	EffectivePeriod   *FHIRPeriod           `json:"effectivePeriod,omitempty"`
+	ValueQuantity     *FHIRQuantity         `json:"valueQuantity,omitempty"`
+}
+
+// FHIRCodeableConcept is a FHIR CodeableConcept.
+type FHIRCodeableConcept struct {
+	Coding []FHIRCoding `json:"coding,omitempty"`
+	Text   string       `json:"text,omitempty"`
+}
+
+// FHIRCoding is a FHIR Coding.
+type FHIRCoding struct {
+	System  string `json:"system"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+// FHIRReference is a FHIR Reference, for example to the Patient the observation is about.
+type FHIRReference struct {
+	Reference string `json:"reference"`
+}
+
+// FHIRPeriod is a FHIR Period.  Start and End are FHIR dateTime values which may be a date only.
+type FHIRPeriod struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// FHIRQuantity is a FHIR Quantity with a UCUM unit.
+type FHIRQuantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	System string  `json:"system"`
+	Code   string  `json:"code"`
+}
+
+// FHIRConverter converts go_oura types to FHIR Observations.
+type FHIRConverter struct {
+	// Subject is the reference written to the subject of every observation, for example "Patient/123".  When empty
+	// no subject is written.
+	Subject string
+}
+
+// HeartRateObservations returns a heart rate observation (LOINC 8867-4, /min) per HeartRate sample.
+func (c FHIRConverter) HeartRateObservations(items []HeartRate) []FHIRObservation {
+	observations := make([]FHIRObservation, 0, len(items))
+	for _, item := range items {
+		observation := c.observation(
+			"heart-rate-"+strconv.FormatInt(item.Timestamp.Unix(), 10),
+			fhirCategoryVitals,
+			FHIRCodeableConcept{
+				Coding: []FHIRCoding{{System: fhirLoincSystem, Code: "8867-4", Display: "Heart rate"}},
+				Text:   "Heart rate",
+			},
+			FHIRQuantity{Value: float64(item.Bpm), Unit: "beats/minute", System: fhirUcumSystem, Code: "/min"},
+		)
+		observation.EffectiveDateTime = item.Timestamp.Format(fhirDateTimeLayout)
+		observations = append(observations, observation)
+	}
+	return observations
+}
+
+// Spo2Observations returns an oxygen saturation observation (LOINC 59408-5 and 2708-6, %) per DailySpo2Reading.
+// The effective period is the day of the reading.
+func (c FHIRConverter) Spo2Observations(items []DailySpo2Reading) []FHIRObservation {
+	observations := make([]FHIRObservation, 0, len(items))
+	for _, item := range items {
+		observation := c.observation(
+			"spo2-"+item.ID,
+			fhirCategoryVitals,
+			FHIRCodeableConcept{
+				Coding: []FHIRCoding{
+					{System: fhirLoincSystem, Code: "59408-5", Display: "Oxygen saturation in Arterial blood by Pulse oximetry"},
+					{System: fhirLoincSystem, Code: "2708-6", Display: "Oxygen saturation in Arterial blood"},
+				},
+				Text: "Average oxygen saturation during sleep",
+			},
+			FHIRQuantity{Value: item.Percentage.Average, Unit: "%", System: fhirUcumSystem, Code: "%"},
+		)
+		observation.EffectivePeriod = fhirDayPeriod(item.Day)
+		observations = append(observations, observation)
+	}
+	return observations
+}
+
+// SleepDurationObservations returns a sleep duration observation (LOINC 93832-4, h) per Sleep with the bedtime
+// start and end as the effective period.
+func (c FHIRConverter) SleepDurationObservations(items []Sleep) []FHIRObservation {
+	observations := make([]FHIRObservation, 0, len(items))
+	for _, item := range items {
+		observation := c.observation(
+			"sleep-duration-"+item.ID,
+			fhirCategoryActivity,
+			FHIRCodeableConcept{
+				Coding: []FHIRCoding{{System: fhirLoincSystem, Code: "93832-4", Display: "Sleep duration"}},
+				Text:   "Sleep duration",
+			},
+			FHIRQuantity{
+				Value:  float64(item.TotalSleepDuration) / float64(time.Hour/time.Second),
+				Unit:   "h",
+				System: fhirUcumSystem,
+				Code:   "h",
+			},
+		)
+		observation.EffectivePeriod = &FHIRPeriod{
+			Start: item.BedtimeStart.Format(fhirDateTimeLayout),
+			End:   item.BedtimeEnd.Format(fhirDateTimeLayout),
+		}
+		observations = append(observations, observation)
+	}
+	return observations
+}
+
+// StepsObservations returns a daily step count observation (LOINC 41950-7, /d) per DailyActivity.  The effective
+// period is the day of the activity.
+func (c FHIRConverter) StepsObservations(items []DailyActivity) []FHIRObservation {
+	observations := make([]FHIRObservation, 0, len(items))
+	for _, item := range items {
+		observation := c.observation(
+			"steps-"+item.ID,
+			fhirCategoryActivity,
+			FHIRCodeableConcept{
+				Coding: []FHIRCoding{{System: fhirLoincSystem, Code: "41950-7", Display: "Number of steps in 24 hour Measured"}},
+				Text:   "Steps",
+			},
+			FHIRQuantity{Value: float64(item.Steps), Unit: "steps/day", System: fhirUcumSystem, Code: "/d"},
+		)
+		observation.EffectivePeriod = fhirDayPeriod(item.Day)
+		observations = append(observations, observation)
+	}
+	return observations
+}
+
+// TemperatureDeviationObservations returns a body temperature deviation observation (Cel) per DailyReadiness.
+// LOINC has no code for a deviation from a personal baseline, and body temperature (LOINC 8310-5) would be read as
+// an absolute temperature, so the observation is only coded with the Oura specific temperature_deviation code.  It has
+// no category, as the vital-signs profile requires a LOINC code.
+func (c FHIRConverter) TemperatureDeviationObservations(items []DailyReadiness) []FHIRObservation {
+	observations := make([]FHIRObservation, 0, len(items))
+	for _, item := range items {
+		observation := c.observation(
+			"temperature-deviation-"+item.Id,
+			"",
+			FHIRCodeableConcept{
+				Coding: []FHIRCoding{
+					{System: fhirOuraSystem, Code: "temperature_deviation", Display: "Body temperature deviation"},
+				},
+				Text: "Body temperature deviation from personal baseline",
+			},
+			FHIRQuantity{Value: item.TemperatureDeviation, Unit: "Cel", System: fhirUcumSystem, Code: "Cel"},
+		)
+		observation.EffectivePeriod = fhirDayPeriod(item.Day)
+		observations = append(observations, observation)
+	}
+	return observations
+}
+
+// observation returns an Observation of code and value, in category unless category is empty.
+func (c FHIRConverter) observation(id string, category string, code FHIRCodeableConcept, value FHIRQuantity) FHIRObservation {
+	observation := FHIRObservation{
+		ResourceType:  "Observation",
+		ID:            id,
+		Status:        "final",
+		Code:          code,
+		ValueQuantity: &value,
+	}
+	if category != "" {
+		categoryDisplay := "Activity"
+		if category == fhirCategoryVitals {
+			categoryDisplay = "Vital Signs"
+		}
+		observation.Category = []FHIRCodeableConcept{
+			{Coding: []FHIRCoding{{System: fhirCategorySystem, Code: category, Display: categoryDisplay}}},
+		}
+	}
+	if c.Subject != "" {
+		observation.Subject = &FHIRReference{Reference: c.Subject}
+	}
+
+	return observation
+}
+
+func fhirDayPeriod(day Date) *FHIRPeriod {
+	return &FHIRPeriod{
+		Start: day.Format(fhirDateLayout),
+		End:   day.Format(fhirDateLayout),
+	}
+}
+
+// NewFHIRBundle returns a collection Bundle holding observations.
+func NewFHIRBundle(observations ...FHIRObservation) FHIRBundle {
+	bundle := FHIRBundle{
+		ResourceType: "Bundle",
+		Type:         "collection",
+		Timestamp:    time.Now().Format(fhirDateTimeLayout),
+		Entry:        make([]FHIRBundleEntry, len(observations)),
+	}
+	for i, observation := range observations {
+		bundle.Entry[i] = FHIRBundleEntry{Resource: observation}
+	}
+	return bundle
+}
+
+// WriteFHIRBundle writes bundle to w as indented JSON.
+func WriteFHIRBundle(w io.Writer, bundle FHIRBundle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to write FHIR bundle with error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// This file contains an encoder writing Workouts and Sessions as Garmin FIT activity files.
+//
+// Each file holds a single activity: file_id, timer start event, one record per heart rate sample, timer stop event,
+// one lap, one session and the activity message.  Only the fields go_oura has values for are written.
+// The FIT protocol is described at https://developer.garmin.com/fit/protocol/
+
+package go_oura
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// fitEpoch is the FIT epoch, 1989-12-31T00:00:00Z, as a unix timestamp.
+const fitEpoch = 631065600
+
+const (
+	fitMessageFileId   = 0
+	fitMessageSession  = 18
+	fitMessageLap      = 19
+	fitMessageRecord   = 20
+	fitMessageEvent    = 21
+	fitMessageActivity = 34
+)
+
+const (
+	fitEnum    = 0x00
+	fitUint8   = 0x02
+	fitUint16  = 0x84
+	fitUint32  = 0x86
+	fitUint32z = 0x8C
+)
+
+const (
+	fitInvalidUint8  = 0xFF
+	fitInvalidUint16 = 0xFFFF
+	fitInvalidUint32 = 0xFFFFFFFF
+)
+
+type fitField struct {
+	number   uint8
+	baseType uint8
+	value    uint32
+}
+
+type fitActivity struct {
+	sport      uint8
+	start      time.Time
+	end        time.Time
+	calories   uint16
+	distance   uint32
+	heartRates []HeartRate
+}
+
+// WriteWorkoutFIT writes workout to w as a FIT activity file.  Records are written for the heartRates samples within
+// the workout window; heartRates does not need to be sorted and may cover more than the workout.
+func WriteWorkoutFIT(w io.Writer, workout Workout, heartRates []HeartRate) error {
+	sorted := make([]HeartRate, len(heartRates))
+	copy(sorted, heartRates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	first := sort.Search(len(sorted), func(i int) bool { return !sorted[i].Timestamp.Before(workout.StartDatetime) })
+	last := sort.Search(len(sorted), func(i int) bool { return sorted[i].Timestamp.After(workout.EndDatetime) })
+	if last < first {
+		last = first
+	}
+
+	return writeFITActivity(w, fitActivity{
+		sport:      fitSport(workout.Activity),
+		start:      workout.StartDatetime,
+		end:        workout.EndDatetime,
+		calories:   uint16(math.Min(math.Round(workout.Calories), fitInvalidUint16-1)),
+		distance:   uint32(math.Min(math.Round(workout.Distance*100), fitInvalidUint32-1)),
+		heartRates: sorted[first:last],
+	})
+}
+
+// WriteSessionFIT writes session to w as a FIT activity file with a record per valid HeartRateData sample.
+func WriteSessionFIT(w io.Writer, session Session) error {
+	var heartRates []HeartRate
+	for _, sample := range IntervalItems(session.HeartRateData).ValidSamples() {
+		heartRates = append(heartRates, HeartRate{
+			Bpm:       int(math.Round(sample.Value)),
+			Source:    HeartRateSourceSession,
+			Timestamp: sample.Time,
+		})
+	}
+
+	return writeFITActivity(w, fitActivity{
+		sport:      0,
+		start:      session.StartDatetime,
+		end:        session.EndDatetime,
+		calories:   fitInvalidUint16,
+		distance:   fitInvalidUint32,
+		heartRates: heartRates,
+	})
+}
+
+// WriteWorkoutFIT fetches the heart rates recorded during workout and writes it to w as a FIT activity file.
+func (c *Client) WriteWorkoutFIT(w io.Writer, workout Workout) error {
+	heartRates, err := c.heartRatesBetween(workout.StartDatetime, workout.EndDatetime)
+	if err != nil {
+		return err
+	}
+
+	return WriteWorkoutFIT(w, workout, heartRates)
+}
+
+// fitSports maps a workoutSport to its FIT sport.  Generic is 0.
+var fitSports = map[workoutSport]uint8{
+	workoutSportRunning:  1,
+	workoutSportCycling:  2,
+	workoutSportSwimming: 5,
+	workoutSportTraining: 10,
+	workoutSportWalking:  11,
+	workoutSportRowing:   15,
+	workoutSportHiking:   17,
+}
+
+// fitSport maps a Workout activity to a FIT sport, falling back to generic.
+func fitSport(activity WorkoutActivity) uint8 {
+	return fitSports[sportOf(activity)]
+}
+
+func writeFITActivity(w io.Writer, activity fitActivity) error {
+	var encoder fitEncoder
+	start := fitTime(activity.start)
+	end := fitTime(activity.end)
+	elapsed := uint32(math.Max(activity.end.Sub(activity.start).Seconds()*1000, 0))
+
+	encoder.message(fitMessageFileId,
+		fitField{0, fitEnum, 4},
+		fitField{1, fitUint16, 255},
+		fitField{2, fitUint16, 0},
+		fitField{3, fitUint32z, 0},
+		fitField{4, fitUint32, end},
+	)
+	encoder.message(fitMessageEvent,
+		fitField{253, fitUint32, start},
+		fitField{0, fitEnum, 0},
+		fitField{1, fitEnum, 0},
+	)
+
+	averageHeartRate, maximumHeartRate := uint32(fitInvalidUint8), uint32(fitInvalidUint8)
+	total, maximum := 0, 0
+	for _, heartRate := range activity.heartRates {
+		bpm := min(max(heartRate.Bpm, 0), fitInvalidUint8-1)
+		encoder.message(fitMessageRecord,
+			fitField{253, fitUint32, fitTime(heartRate.Timestamp)},
+			fitField{3, fitUint8, uint32(bpm)},
+		)
+		total += bpm
+		maximum = max(maximum, bpm)
+	}
+	if len(activity.heartRates) > 0 {
+		averageHeartRate = uint32(math.Round(float64(total) / float64(len(activity.heartRates))))
+		maximumHeartRate = uint32(maximum)
+	}
+
+	encoder.message(fitMessageEvent,
+		fitField{253, fitUint32, end},
+		fitField{0, fitEnum, 0},
+		fitField{1, fitEnum, 4},
+	)
+	encoder.message(fitMessageLap,
+		fitField{253, fitUint32, end},
+		fitField{0, fitEnum, 9},
+		fitField{1, fitEnum, 1},
+		fitField{2, fitUint32, start},
+		fitField{7, fitUint32, elapsed},
+		fitField{8, fitUint32, elapsed},
+		fitField{9, fitUint32, activity.distance},
+		fitField{11, fitUint16, uint32(activity.calories)},
+		fitField{15, fitUint8, averageHeartRate},
+		fitField{16, fitUint8, maximumHeartRate},
+		fitField{25, fitEnum, uint32(activity.sport)},
+	)
+	encoder.message(fitMessageSession,
+		fitField{253, fitUint32, end},
+		fitField{0, fitEnum, 8},
+		fitField{1, fitEnum, 1},
+		fitField{2, fitUint32, start},
+		fitField{5, fitEnum, uint32(activity.sport)},
+		fitField{7, fitUint32, elapsed},
+		fitField{8, fitUint32, elapsed},
+		fitField{9, fitUint32, activity.distance},
+		fitField{11, fitUint16, uint32(activity.calories)},
+		fitField{16, fitUint8, averageHeartRate},
+		fitField{17, fitUint8, maximumHeartRate},
+		fitField{25, fitUint16, 0},
+		fitField{26, fitUint16, 1},
+	)
+
+	_, offset := activity.end.Zone()
+	encoder.message(fitMessageActivity,
+		fitField{253, fitUint32, end},
+		fitField{0, fitUint32, elapsed},
+		fitField{1, fitUint16, 1},
+		fitField{2, fitEnum, 0},
+		fitField{3, fitEnum, 26},
+		fitField{4, fitEnum, 1},
+		fitField{5, fitUint32, uint32(int64(end) + int64(offset))},
+	)
+
+	if _, err := encoder.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write FIT with error: %v", err)
+	}
+
+	return nil
+}
+
+func fitTime(t time.Time) uint32 {
+	return uint32(t.Unix() - fitEpoch)
+}
+
+// fitEncoder buffers FIT messages, writing a definition message the first time each global message is used.  Every
+// use of a global message must pass the same fields.
+type fitEncoder struct {
+	data    bytes.Buffer
+	locals  map[uint16]uint8
+	scratch [4]byte
+}
+
+func (e *fitEncoder) message(global uint16, fields ...fitField) {
+	local, ok := e.locals[global]
+	if !ok {
+		if e.locals == nil {
+			e.locals = make(map[uint16]uint8)
+		}
+		local = uint8(len(e.locals))
+		e.locals[global] = local
+
+		e.data.Write([]byte{0x40 | local, 0, 0})
+		_ = binary.Write(&e.data, binary.LittleEndian, global)
+		e.data.WriteByte(uint8(len(fields)))
+		for _, field := range fields {
+			e.data.Write([]byte{field.number, fitFieldSize(field.baseType), field.baseType})
+		}
+	}
+
+	e.data.WriteByte(local)
+	for _, field := range fields {
+		binary.LittleEndian.PutUint32(e.scratch[:], field.value)
+		e.data.Write(e.scratch[:fitFieldSize(field.baseType)])
+	}
+}
+
+// WriteTo writes the file header, the buffered messages and the file CRC to w.
+func (e *fitEncoder) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, 14, 14+e.data.Len()+2)
+	header[0] = 14
+	header[1] = 0x10
+	binary.LittleEndian.PutUint16(header[2:], 2132)
+	binary.LittleEndian.PutUint32(header[4:], uint32(e.data.Len()))
+	copy(header[8:], ".FIT")
+	binary.LittleEndian.PutUint16(header[12:], fitCRC(header[:12]))
+
+	file := append(header, e.data.Bytes()...)
+	file = binary.LittleEndian.AppendUint16(file, fitCRC(file))
+
+	n, err := w.Write(file)
+	return int64(n), err
+}
+
+func fitFieldSize(baseType uint8) uint8 {
+	switch baseType {
+	case fitUint16:
+		return 2
+	case fitUint32, fitUint32z:
+		return 4
+	default:
+		return 1
+	}
+}
+
+var fitCRCTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[b&0xF]
+
+		tmp = fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[(b>>4)&0xF]
+	}
+	return crc
+}
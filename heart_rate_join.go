@@ -0,0 +1,228 @@
+// This file contains the joining of heart rate samples to workouts and sessions.
+//
+// Neither Workout nor Session carries the heart rate stream recorded during it.  HeartRateJoiner attaches the samples
+// from GetHeartRates by time window and source, sorting the samples and windows once and sweeping both together so a
+// whole date range is joined without a lookup per workout.
+
+package go_oura
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// HeartRateJoiner attaches heart rate samples to workouts and sessions.
+type HeartRateJoiner struct {
+	// Zones are used for HeartRateSummary.TimeInZone.  A sample is counted in the first zone containing it.
+	Zones []HeartRateZone
+
+	// RecoveryWindow is the time after the end of a workout or session at which recovery is measured.
+	RecoveryWindow time.Duration
+
+	// MaxSampleGap caps the time a single sample is counted for, so gaps in the stream are not counted in a zone.
+	MaxSampleGap time.Duration
+
+	// WorkoutSources and SessionSources are the sources of the samples attached to workouts and sessions.
+	WorkoutSources []HeartRateSource
+	SessionSources []HeartRateSource
+}
+
+// HeartRateSummary holds the heart rate samples recorded during a workout or session and metrics derived from them.
+type HeartRateSummary struct {
+	Samples []HeartRate
+	Average float64
+	Min     int
+	Max     int
+
+	// Recovery is the drop in bpm from the last sample to the first sample of any source at least RecoveryWindow
+	// later.  It is nil when either sample is missing.
+	Recovery *int
+
+	TimeInZone map[string]time.Duration
+}
+
+// WorkoutHeartRate is a Workout with the heart rate recorded during it.
+type WorkoutHeartRate struct {
+	Workout
+	HeartRate HeartRateSummary
+}
+
+// SessionHeartRate is a Session with the heart rate recorded during it.
+type SessionHeartRate struct {
+	Session
+	HeartRate HeartRateSummary
+}
+
+// NewHeartRateJoiner returns a HeartRateJoiner using zones with a one minute recovery window, a five minute sample
+// gap, and the workout, session and live sources.
+func NewHeartRateJoiner(zones []HeartRateZone) *HeartRateJoiner {
+	return &HeartRateJoiner{
+		Zones:          zones,
+		RecoveryWindow: time.Minute,
+		MaxSampleGap:   5 * time.Minute,
+		WorkoutSources: []HeartRateSource{HeartRateSourceWorkout, HeartRateSourceLive},
+		SessionSources: []HeartRateSource{HeartRateSourceSession, HeartRateSourceLive},
+	}
+}
+
+// JoinWorkouts returns workouts, in the same order, with the heartRates recorded during each.
+func (j *HeartRateJoiner) JoinWorkouts(workouts []Workout, heartRates []HeartRate) []WorkoutHeartRate {
+	windows := make([]heartRateWindow, len(workouts))
+	for i, workout := range workouts {
+		windows[i] = heartRateWindow{start: workout.StartDatetime, end: workout.EndDatetime, sources: j.WorkoutSources}
+	}
+
+	summaries := j.join(windows, heartRates)
+	joined := make([]WorkoutHeartRate, len(workouts))
+	for i, workout := range workouts {
+		joined[i] = WorkoutHeartRate{Workout: workout, HeartRate: summaries[i]}
+	}
+	return joined
+}
+
+// JoinSessions returns sessions, in the same order, with the heartRates recorded during each.
+func (j *HeartRateJoiner) JoinSessions(sessions []Session, heartRates []HeartRate) []SessionHeartRate {
+	windows := make([]heartRateWindow, len(sessions))
+	for i, session := range sessions {
+		windows[i] = heartRateWindow{start: session.StartDatetime, end: session.EndDatetime, sources: j.SessionSources}
+	}
+
+	summaries := j.join(windows, heartRates)
+	joined := make([]SessionHeartRate, len(sessions))
+	for i, session := range sessions {
+		joined[i] = SessionHeartRate{Session: session, HeartRate: summaries[i]}
+	}
+	return joined
+}
+
+// GetWorkoutHeartRates returns the workouts from startDate through endDate with the heart rates recorded during each,
+// joined by a HeartRateJoiner using zones.
+func (c *Client) GetWorkoutHeartRates(startDate time.Time, endDate time.Time, zones []HeartRateZone) ([]WorkoutHeartRate, error) {
+	documents, err := c.fetchAllDocuments(ResourceWorkout, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	workouts := make([]Workout, 0, len(documents))
+	for _, document := range documents {
+		workouts = append(workouts, document.(Workout))
+	}
+	if len(workouts) == 0 {
+		return nil, nil
+	}
+
+	joiner := NewHeartRateJoiner(zones)
+	start, end := workouts[0].StartDatetime, workouts[0].EndDatetime
+	for _, workout := range workouts {
+		start = minTime(start, workout.StartDatetime)
+		end = maxTime(end, workout.EndDatetime)
+	}
+
+	heartRates, err := c.heartRatesBetween(start, end.Add(2*joiner.RecoveryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return joiner.JoinWorkouts(workouts, heartRates), nil
+}
+
+type heartRateWindow struct {
+	start   time.Time
+	end     time.Time
+	sources []HeartRateSource
+}
+
+// join summarizes the heartRates of each window.  The samples and the windows are sorted by time, and the first
+// sample a window can use only moves forward, so each window scans only the samples overlapping it.
+func (j *HeartRateJoiner) join(windows []heartRateWindow, heartRates []HeartRate) []HeartRateSummary {
+	sorted := slices.Clone(heartRates)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Timestamp.Before(sorted[b].Timestamp) })
+
+	order := make([]int, len(windows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return windows[order[a]].start.Before(windows[order[b]].start) })
+
+	summaries := make([]HeartRateSummary, len(windows))
+	first := 0
+	for _, w := range order {
+		window := windows[w]
+		for first < len(sorted) && sorted[first].Timestamp.Before(window.start) {
+			first++
+		}
+
+		var samples []HeartRate
+		var recovery *HeartRate
+		recoveryAt := window.end.Add(j.RecoveryWindow)
+		for i := first; i < len(sorted); i++ {
+			sample := sorted[i]
+			if !sample.Timestamp.After(window.end) {
+				if slices.Contains(window.sources, sample.Source) {
+					samples = append(samples, sample)
+				}
+				continue
+			}
+			if !sample.Timestamp.Before(recoveryAt) {
+				if sample.Timestamp.Before(recoveryAt.Add(j.RecoveryWindow)) {
+					recovery = &sorted[i]
+				}
+				break
+			}
+		}
+
+		summaries[w] = j.summarize(samples, window.end, recovery)
+	}
+
+	return summaries
+}
+
+func (j *HeartRateJoiner) summarize(samples []HeartRate, end time.Time, recovery *HeartRate) HeartRateSummary {
+	summary := HeartRateSummary{Samples: samples, TimeInZone: make(map[string]time.Duration)}
+	if len(samples) == 0 {
+		return summary
+	}
+
+	total := 0
+	summary.Min, summary.Max = samples[0].Bpm, samples[0].Bpm
+	for i, sample := range samples {
+		total += sample.Bpm
+		summary.Min = min(summary.Min, sample.Bpm)
+		summary.Max = max(summary.Max, sample.Bpm)
+
+		next := end
+		if i+1 < len(samples) {
+			next = samples[i+1].Timestamp
+		}
+		duration := next.Sub(sample.Timestamp)
+		if j.MaxSampleGap > 0 {
+			duration = min(duration, j.MaxSampleGap)
+		}
+		if zone, ok := heartRateZoneFor(j.Zones, sample.Bpm); ok {
+			summary.TimeInZone[zone] += duration
+		}
+	}
+	summary.Average = float64(total) / float64(len(samples))
+
+	if recovery != nil {
+		drop := samples[len(samples)-1].Bpm - recovery.Bpm
+		summary.Recovery = &drop
+	}
+
+	return summary
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,212 @@
+// This file contains HeartRateZone, a named range of heart rates used for time in zone metrics.
+//
+// Zones are personal, so they are built from a maximum heart rate estimated from PersonalInfo.Age or supplied by
+// the user, optionally with a resting heart rate for Karvonen zones.  The resting heart rate can be derived from the
+// lowest heart rate of recent sleeps.
+
+package go_oura
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// HeartRateZone is a named range of heart rates, from MinBpm inclusive to MaxBpm exclusive.  A MaxBpm of zero or
+// less has no upper limit.
+type HeartRateZone struct {
+	Name   string
+	MinBpm int
+	MaxBpm int
+}
+
+// Contains reports whether bpm is within the zone.
+func (z HeartRateZone) Contains(bpm int) bool {
+	return bpm >= z.MinBpm && (z.MaxBpm <= 0 || bpm < z.MaxBpm)
+}
+
+// MaxHeartRateFormula is a formula estimating maximum heart rate from age.
+type MaxHeartRateFormula int
+
+const (
+	// MaxHeartRateFox is 220 - age.
+	MaxHeartRateFox MaxHeartRateFormula = iota
+	// MaxHeartRateTanaka is 208 - 0.7 * age.
+	MaxHeartRateTanaka
+	// MaxHeartRateGellish is 207 - 0.7 * age.
+	MaxHeartRateGellish
+	// MaxHeartRateNes is 211 - 0.64 * age.
+	MaxHeartRateNes
+)
+
+// heartRateZoneBounds are the fractions of maximum heart rate, or of heart rate reserve for Karvonen zones, bounding
+// the five zones.
+var heartRateZoneBounds = []float64{0.5, 0.6, 0.7, 0.8, 0.9}
+
+// EstimateMaxHeartRate returns the maximum heart rate estimated by formula for age.
+func EstimateMaxHeartRate(age int, formula MaxHeartRateFormula) (int, error) {
+	if age <= 0 {
+		return 0, fmt.Errorf("failed to estimate max heart rate with invalid age %d", age)
+	}
+
+	a := float64(age)
+	switch formula {
+	case MaxHeartRateFox:
+		return 220 - age, nil
+	case MaxHeartRateTanaka:
+		return int(math.Round(208 - 0.7*a)), nil
+	case MaxHeartRateGellish:
+		return int(math.Round(207 - 0.7*a)), nil
+	case MaxHeartRateNes:
+		return int(math.Round(211 - 0.64*a)), nil
+	}
+	return 0, fmt.Errorf("failed to estimate max heart rate with unknown formula %d", formula)
+}
+
+// MaxHeartRate returns the maximum heart rate estimated by formula from the user's age.
+func (pi PersonalInfo) MaxHeartRate(formula MaxHeartRateFormula) (int, error) {
+	return EstimateMaxHeartRate(pi.Age, formula)
+}
+
+// HeartRateZones returns five zones, "zone 1" through "zone 5", starting at 50, 60, 70, 80 and 90 percent of
+// maxBpm.  The last zone has no upper limit.
+func HeartRateZones(maxBpm int) ([]HeartRateZone, error) {
+	if maxBpm <= 0 {
+		return nil, fmt.Errorf("failed to build heart rate zones with invalid max heart rate %d", maxBpm)
+	}
+	return zonesFromBounds(0, maxBpm), nil
+}
+
+// KarvonenHeartRateZones returns five zones, "zone 1" through "zone 5", starting at 50, 60, 70, 80 and 90 percent of
+// the heart rate reserve, maxBpm - restingBpm, above restingBpm.  The last zone has no upper limit.
+func KarvonenHeartRateZones(maxBpm int, restingBpm int) ([]HeartRateZone, error) {
+	if restingBpm <= 0 || maxBpm <= restingBpm {
+		return nil, fmt.Errorf("failed to build heart rate zones with invalid max %d and resting %d heart rates", maxBpm, restingBpm)
+	}
+	return zonesFromBounds(restingBpm, maxBpm), nil
+}
+
+// RestingHeartRate returns the median Sleep.LowestHeartRate of sleeps, ignoring deleted sleeps and sleeps without
+// a lowest heart rate.  The second value is false when no sleep has one.
+func RestingHeartRate(sleeps []Sleep) (int, bool) {
+	var lowest []int
+	for _, sleep := range sleeps {
+		if sleep.Type != SleepTypeDeleted && sleep.LowestHeartRate > 0 {
+			lowest = append(lowest, sleep.LowestHeartRate)
+		}
+	}
+	if len(lowest) == 0 {
+		return 0, false
+	}
+
+	slices.Sort(lowest)
+	middle := len(lowest) / 2
+	if len(lowest)%2 == 1 {
+		return lowest[middle], true
+	}
+	return int(math.Round(float64(lowest[middle-1]+lowest[middle]) / 2)), true
+}
+
+func zonesFromBounds(restingBpm int, maxBpm int) []HeartRateZone {
+	reserve := float64(maxBpm - restingBpm)
+	zones := make([]HeartRateZone, len(heartRateZoneBounds))
+	for i, bound := range heartRateZoneBounds {
+		zones[i] = HeartRateZone{Name: fmt.Sprintf("zone %d", i+1), MinBpm: restingBpm + int(math.Round(bound*reserve))}
+		if i > 0 {
+			zones[i-1].MaxBpm = zones[i].MinBpm
+		}
+	}
+	return zones
+}
+
+// HeartRateZoneTime is the time spent in a HeartRateZone.  Percent is of HeartRateZoneReport.Total.
+type HeartRateZoneTime struct {
+	Zone     HeartRateZone
+	Duration time.Duration
+	Percent  float64
+}
+
+// HeartRateZoneReport holds the time spent in each zone.  Outside is the time below, above or between the zones.
+type HeartRateZoneReport struct {
+	Zones   []HeartRateZoneTime
+	Outside time.Duration
+	Total   time.Duration
+}
+
+// BucketHeartRates returns the time heartRates spent in each of zones.  Each sample counts until the next one,
+// capped at maxGap so gaps in the stream are not counted, and the last sample counts as long as the one before it.
+func BucketHeartRates(heartRates []HeartRate, zones []HeartRateZone, maxGap time.Duration) HeartRateZoneReport {
+	sorted := slices.Clone(heartRates)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	report := newHeartRateZoneReport(zones)
+	var previous time.Duration
+	for i, sample := range sorted {
+		duration := previous
+		if i+1 < len(sorted) {
+			duration = sorted[i+1].Timestamp.Sub(sample.Timestamp)
+		}
+		if maxGap > 0 {
+			duration = min(duration, maxGap)
+		}
+		previous = duration
+
+		report.add(sample.Bpm, duration)
+	}
+	report.percentages()
+
+	return report
+}
+
+// BucketIntervalItems returns the time the heart rate series ii spent in each of zones.  Each valid sample counts
+// for the interval and gaps are not counted.
+func BucketIntervalItems(ii IntervalItems, zones []HeartRateZone) HeartRateZoneReport {
+	report := newHeartRateZoneReport(zones)
+	interval := time.Duration(ii.Interval * float64(time.Second))
+	for _, sample := range ii.ValidSamples() {
+		report.add(int(math.Round(sample.Value)), interval)
+	}
+	report.percentages()
+
+	return report
+}
+
+func newHeartRateZoneReport(zones []HeartRateZone) HeartRateZoneReport {
+	report := HeartRateZoneReport{Zones: make([]HeartRateZoneTime, len(zones))}
+	for i, zone := range zones {
+		report.Zones[i].Zone = zone
+	}
+	return report
+}
+
+func (r *HeartRateZoneReport) add(bpm int, duration time.Duration) {
+	r.Total += duration
+	for i := range r.Zones {
+		if r.Zones[i].Zone.Contains(bpm) {
+			r.Zones[i].Duration += duration
+			return
+		}
+	}
+	r.Outside += duration
+}
+
+func (r *HeartRateZoneReport) percentages() {
+	if r.Total == 0 {
+		return
+	}
+	for i := range r.Zones {
+		r.Zones[i].Percent = 100 * float64(r.Zones[i].Duration) / float64(r.Total)
+	}
+}
+
+// heartRateZoneFor returns the name of the first of zones containing bpm.
+func heartRateZoneFor(zones []HeartRateZone, bpm int) (string, bool) {
+	for _, zone := range zones {
+		if zone.Contains(bpm) {
+			return zone.Name, true
+		}
+	}
+	return "", false
+}
@@ -22,9 +22,9 @@ type HeartRates struct {
 
 // HeartRate stores specifics for a recorded heart rate by the Oura Ring
 type HeartRate struct {
-	Bpm       int       `json:"bpm"`
-	Source    string    `json:"source"`
-	Timestamp time.Time `json:"timestamp"`
+	Bpm       int             `json:"bpm"`
+	Source    HeartRateSource `json:"source"`
+	Timestamp time.Time       `json:"timestamp"`
 }
 
 type heartRatesBase HeartRates
@@ -94,3 +94,40 @@ func (c *Client) GetHeartRates(startDateTime time.Time, endDateTime time.Time, n
 
 	return heartrates, nil
 }
+
+// GetHeartRatesForDays returns every HeartRate from the start of startDay up to the end of endDay in loc.  loc
+// should be the user's time zone, for example a ZoneModel Location or a location loaded with time.LoadLocation.
+func (c *Client) GetHeartRatesForDays(startDay Date, endDay Date, loc *time.Location) ([]HeartRate, error) {
+	start, end := startDay.StartOfDay(loc), endDay.EndOfDay(loc)
+
+	heartRates, err := c.heartRatesBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := heartRates[:0]
+	for _, heartRate := range heartRates {
+		if !heartRate.Timestamp.Before(start) && heartRate.Timestamp.Before(end) {
+			inRange = append(inRange, heartRate)
+		}
+	}
+	return inRange, nil
+}
+
+// heartRatesBetween pages through GetHeartRates and returns every HeartRate between start and end.
+func (c *Client) heartRatesBetween(start time.Time, end time.Time) ([]HeartRate, error) {
+	var heartRates []HeartRate
+	var nextToken *string
+	for {
+		page, err := c.GetHeartRates(start, end, nextToken)
+		if err != nil {
+			return nil, err
+		}
+		heartRates = append(heartRates, page.Items...)
+
+		nextToken = optionalToken(page.NextToken)
+		if nextToken == nil {
+			return heartRates, nil
+		}
+	}
+}
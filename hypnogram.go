@@ -0,0 +1,199 @@
+// This file contains decoding of Sleep.SleepPhase5Min into a typed hypnogram.
+//
+// SleepPhase5Min holds a digit per 5 minutes of the sleep period, starting at BedtimeStart:
+// 1 = deep sleep, 2 = light sleep, 3 = REM sleep, 4 = awake.
+
+package go_oura
+
+import (
+	"fmt"
+	"time"
+)
+
+// SleepPhaseInterval is the duration covered by each digit of Sleep.SleepPhase5Min.
+const SleepPhaseInterval = 5 * time.Minute
+
+// SleepStage is a sleep stage as encoded in Sleep.SleepPhase5Min.
+type SleepStage int
+
+const (
+	SleepStageDeep  SleepStage = 1
+	SleepStageLight SleepStage = 2
+	SleepStageREM   SleepStage = 3
+	SleepStageAwake SleepStage = 4
+)
+
+// SleepStages returns every SleepStage in encoding order.
+func SleepStages() []SleepStage {
+	return []SleepStage{SleepStageDeep, SleepStageLight, SleepStageREM, SleepStageAwake}
+}
+
+func (s SleepStage) String() string {
+	switch s {
+	case SleepStageDeep:
+		return "deep"
+	case SleepStageLight:
+		return "light"
+	case SleepStageREM:
+		return "rem"
+	case SleepStageAwake:
+		return "awake"
+	default:
+		return fmt.Sprintf("SleepStage(%d)", int(s))
+	}
+}
+
+// HypnogramSegment is a run of consecutive 5 minute periods spent in the same stage.  End is exclusive.
+type HypnogramSegment struct {
+	Start time.Time
+	End   time.Time
+	Stage SleepStage
+}
+
+// Duration returns the length of the segment.
+func (s HypnogramSegment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// HypnogramTransition is a change from one stage to another.
+type HypnogramTransition struct {
+	At   time.Time
+	From SleepStage
+	To   SleepStage
+}
+
+// HypnogramMismatch reports a stage whose decoded duration differs from the duration reported on the Sleep.
+type HypnogramMismatch struct {
+	Stage    SleepStage
+	Decoded  time.Duration
+	Reported time.Duration
+}
+
+// Hypnogram is a time ordered list of sleep stage segments.
+type Hypnogram []HypnogramSegment
+
+// DecodeHypnogram decodes a SleepPhase5Min string into segments, the first starting at start.  Consecutive periods
+// in the same stage are merged into one segment.
+func DecodeHypnogram(phases string, start time.Time) (Hypnogram, error) {
+	var hypnogram Hypnogram
+	for i, phase := range phases {
+		stage := SleepStage(phase - '0')
+		if stage < SleepStageDeep || stage > SleepStageAwake {
+			return nil, fmt.Errorf("invalid sleep phase %q at index %d", phase, i)
+		}
+
+		periodStart := start.Add(time.Duration(i) * SleepPhaseInterval)
+		periodEnd := periodStart.Add(SleepPhaseInterval)
+		if last := len(hypnogram) - 1; last >= 0 && hypnogram[last].Stage == stage {
+			hypnogram[last].End = periodEnd
+			continue
+		}
+		hypnogram = append(hypnogram, HypnogramSegment{Start: periodStart, End: periodEnd, Stage: stage})
+	}
+	return hypnogram, nil
+}
+
+// Hypnogram decodes SleepPhase5Min anchored at BedtimeStart.  The last segment is cut at BedtimeEnd since the sleep
+// period rarely ends on a 5 minute boundary.
+func (s Sleep) Hypnogram() (Hypnogram, error) {
+	hypnogram, err := DecodeHypnogram(s.SleepPhase5Min, s.BedtimeStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if last := len(hypnogram) - 1; last >= 0 {
+		end := hypnogram[last].End
+		if s.BedtimeEnd.After(hypnogram[last].Start) && s.BedtimeEnd.Before(end) {
+			hypnogram[last].End = s.BedtimeEnd
+		}
+	}
+	return hypnogram, nil
+}
+
+// StageDurations returns the total time spent in each stage.
+func (h Hypnogram) StageDurations() map[SleepStage]time.Duration {
+	durations := make(map[SleepStage]time.Duration)
+	for _, segment := range h {
+		durations[segment.Stage] += segment.Duration()
+	}
+	return durations
+}
+
+// Transitions returns every change of stage in time order.
+func (h Hypnogram) Transitions() []HypnogramTransition {
+	var transitions []HypnogramTransition
+	for i := 1; i < len(h); i++ {
+		transitions = append(transitions, HypnogramTransition{At: h[i].Start, From: h[i-1].Stage, To: h[i].Stage})
+	}
+	return transitions
+}
+
+// SleepOnset returns the start of the first segment of sleep, or false when no sleep was recorded.
+func (h Hypnogram) SleepOnset() (time.Time, bool) {
+	for _, segment := range h {
+		if segment.Stage != SleepStageAwake {
+			return segment.Start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FinalAwakening returns the end of the last segment of sleep, or false when no sleep was recorded.
+func (h Hypnogram) FinalAwakening() (time.Time, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].Stage != SleepStageAwake {
+			return h[i].End, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// WASO returns the wake after sleep onset: the time spent awake between SleepOnset and FinalAwakening.
+func (h Hypnogram) WASO() time.Duration {
+	onset, ok := h.SleepOnset()
+	if !ok {
+		return 0
+	}
+	awakening, _ := h.FinalAwakening()
+
+	var waso time.Duration
+	for _, segment := range h {
+		if segment.Stage == SleepStageAwake && !segment.Start.Before(onset) && !segment.End.After(awakening) {
+			waso += segment.Duration()
+		}
+	}
+	return waso
+}
+
+// StageAt returns the stage at t, or false when t is outside the hypnogram.
+func (h Hypnogram) StageAt(t time.Time) (SleepStage, bool) {
+	for _, segment := range h {
+		if !t.Before(segment.Start) && t.Before(segment.End) {
+			return segment.Stage, true
+		}
+	}
+	return 0, false
+}
+
+// CheckDurations compares the decoded stage totals with DeepSleepDuration, LightSleepDuration, RemSleepDuration and
+// AwakeTime of s, returning the stages which differ by more than tolerance.  Small differences are expected since
+// the API rounds stages to 5 minutes.
+func (h Hypnogram) CheckDurations(s Sleep, tolerance time.Duration) []HypnogramMismatch {
+	reported := map[SleepStage]int{
+		SleepStageDeep:  s.DeepSleepDuration,
+		SleepStageLight: s.LightSleepDuration,
+		SleepStageREM:   s.RemSleepDuration,
+		SleepStageAwake: s.AwakeTime,
+	}
+
+	durations := h.StageDurations()
+	var mismatches []HypnogramMismatch
+	for _, stage := range SleepStages() {
+		expected := time.Duration(reported[stage]) * time.Second
+		difference := durations[stage] - expected
+		if difference > tolerance || difference < -tolerance {
+			mismatches = append(mismatches, HypnogramMismatch{Stage: stage, Decoded: durations[stage], Reported: expected})
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,315 @@
+// This file contains an iCalendar (RFC 5545) generator for overlaying Oura Ring data on calendars.
+//
+// Every event UID is derived from the id of the document it was built from, so importing an updated calendar
+// replaces events rather than duplicating them.  The documents carry no revision history, so SEQUENCE and
+// LAST-MODIFIED are only written when set on the event by a caller which tracks changes, for example by comparing
+// with a previous export.
+
+package go_oura
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalDateTimeLayout = "20060102T150405Z"
+	icalDateLayout     = "20060102"
+	icalLineLimit      = 75
+)
+
+// ICalendarEvent is a single VEVENT.  When AllDay is set only the dates of Start and End are written and End is
+// exclusive.  Suggested events are written as tentative and transparent so they do not block time.  Sequence and
+// LastModified are only written when set.
+type ICalendarEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Suggested    bool
+	Sequence     int
+	LastModified time.Time
+}
+
+// ICalendar builds an iCalendar document from go_oura types.
+type ICalendar struct {
+	// Name is written as the calendar name when set.
+	Name string
+
+	Events []ICalendarEvent
+
+	// Now returns the current time, used for the DTSTAMP of every event, and defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewICalendar returns an empty ICalendar with the given name.
+func NewICalendar(name string) *ICalendar {
+	return &ICalendar{
+		Name: name,
+		Now:  time.Now,
+	}
+}
+
+// AddSleeps adds an event per Sleep from BedtimeStart to BedtimeEnd with the durations and scores in the description.
+// Deleted periods are skipped.
+func (cal *ICalendar) AddSleeps(items []Sleep) {
+	for _, item := range items {
+		if item.Type == SleepTypeDeleted {
+			continue
+		}
+
+		summary := "Sleep"
+		if item.Type != SleepTypeLongSleep {
+			summary = "Nap"
+		}
+
+		description := []string{
+			"Total sleep: " + icalDuration(item.TotalSleepDuration),
+			"Deep: " + icalDuration(item.DeepSleepDuration),
+			"REM: " + icalDuration(item.RemSleepDuration),
+			"Light: " + icalDuration(item.LightSleepDuration),
+			fmt.Sprintf("Efficiency: %d", item.Efficiency),
+			fmt.Sprintf("Readiness score: %d", item.Readiness.Score),
+		}
+
+		cal.Events = append(cal.Events, ICalendarEvent{
+			UID:         "sleep-" + item.ID,
+			Summary:     summary,
+			Description: strings.Join(description, "\n"),
+			Start:       item.BedtimeStart,
+			End:         item.BedtimeEnd,
+		})
+	}
+}
+
+// AddWorkouts adds an event per Workout, summarized by its label or activity.
+func (cal *ICalendar) AddWorkouts(items []Workout) {
+	for _, item := range items {
+		summary := icalTitle(string(item.Activity))
+		if item.Label != "" {
+			summary = item.Label
+		}
+
+		description := []string{
+			"Activity: " + string(item.Activity),
+			"Intensity: " + string(item.Intensity),
+			fmt.Sprintf("Calories: %.0f", item.Calories),
+			fmt.Sprintf("Distance: %.0f m", item.Distance),
+		}
+
+		cal.Events = append(cal.Events, ICalendarEvent{
+			UID:         "workout-" + item.Id,
+			Summary:     summary,
+			Description: strings.Join(description, "\n"),
+			Start:       item.StartDatetime,
+			End:         item.EndDatetime,
+		})
+	}
+}
+
+// AddSessions adds an event per Session, summarized by its type.
+func (cal *ICalendar) AddSessions(items []Session) {
+	for _, item := range items {
+		description := "Type: " + string(item.Type)
+		if item.Mood != "" {
+			description += "\nMood: " + string(item.Mood)
+		}
+
+		cal.Events = append(cal.Events, ICalendarEvent{
+			UID:         "session-" + item.ID,
+			Summary:     icalTitle(string(item.Type)) + " session",
+			Description: description,
+			Start:       item.StartDatetime,
+			End:         item.EndDatetime,
+		})
+	}
+}
+
+// AddRestModes adds an event per RestMode period with its episode tags in the description.  A period without an
+// end time is still ongoing and is written through its start day.
+func (cal *ICalendar) AddRestModes(items []RestMode) {
+	for _, item := range items {
+		var description []string
+		for _, episode := range item.Episodes {
+			description = append(description, episode.Timestamp.UTC().Format(time.RFC3339)+": "+strings.Join(episode.Tags, ", "))
+		}
+
+		event := ICalendarEvent{
+			UID:         "rest-mode-" + item.ID,
+			Summary:     "Rest mode",
+			Description: strings.Join(description, "\n"),
+			Start:       item.StartTime,
+			End:         item.EndTime,
+		}
+		if item.EndTime.IsZero() {
+			event.AllDay = true
+			event.Start = item.StartDay.Time
+			event.End = item.StartDay.AddDate(0, 0, 1)
+		}
+
+		cal.Events = append(cal.Events, event)
+	}
+}
+
+// AddEnhancedTags adds an event per EnhancedTag.  Tags with times cover StartTime to EndTime, or just StartTime when
+// there is no end.  Tags with days only are written as all day events.
+func (cal *ICalendar) AddEnhancedTags(items []EnhancedTag) {
+	for _, item := range items {
+		event := ICalendarEvent{
+			UID:         "tag-" + item.ID,
+			Summary:     icalTitle(strings.TrimPrefix(item.TagTypeCode, "tag_generic_")),
+			Description: item.Comment,
+		}
+
+		switch {
+		case item.StartTime != nil:
+			event.Start = *item.StartTime
+			event.End = *item.StartTime
+			if item.EndTime != nil {
+				event.End = *item.EndTime
+			}
+		case item.StartDay != nil:
+			event.AllDay = true
+			event.Start = item.StartDay.Time
+			event.End = item.StartDay.AddDate(0, 0, 1)
+			if item.EndDay != nil {
+				event.End = item.EndDay.AddDate(0, 0, 1)
+			}
+		default:
+			continue
+		}
+
+		cal.Events = append(cal.Events, event)
+	}
+}
+
+// AddSleepTimes adds a suggested event per SleepTime with an OptimalBedtime window.
+func (cal *ICalendar) AddSleepTimes(items []SleepTime) {
+	for _, item := range items {
+		start, end, ok := item.BedtimeWindow()
+		if !ok {
+			continue
+		}
+
+		cal.Events = append(cal.Events, ICalendarEvent{
+			UID:         "sleep-time-" + item.ID,
+			Summary:     "Suggested bedtime",
+			Description: "Recommendation: " + string(item.Recommendation),
+			Start:       start,
+			End:         end,
+			Suggested:   true,
+		})
+	}
+}
+
+// WriteTo writes the calendar to w with CRLF line endings and lines folded at 75 octets.
+func (cal *ICalendar) WriteTo(w io.Writer) (int64, error) {
+	now := time.Now
+	if cal.Now != nil {
+		now = cal.Now
+	}
+	stamp := now().UTC().Format(icalDateTimeLayout)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go_oura//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	if cal.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+icalEscape(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+icalEscape(event.UID)+"@go_oura",
+			"DTSTAMP:"+stamp,
+		)
+		if !event.LastModified.IsZero() {
+			lines = append(lines, "LAST-MODIFIED:"+event.LastModified.UTC().Format(icalDateTimeLayout))
+		}
+		if event.Sequence != 0 {
+			lines = append(lines, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		}
+
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format(icalDateLayout),
+				"DTEND;VALUE=DATE:"+event.End.Format(icalDateLayout),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART:"+event.Start.UTC().Format(icalDateTimeLayout),
+				"DTEND:"+event.End.UTC().Format(icalDateTimeLayout),
+			)
+		}
+		lines = append(lines, "SUMMARY:"+icalEscape(event.Summary))
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icalEscape(event.Description))
+		}
+		if event.Suggested {
+			lines = append(lines, "STATUS:TENTATIVE", "TRANSP:TRANSPARENT")
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(icalFold(line))
+	}
+
+	n, err := io.WriteString(w, builder.String())
+	if err != nil {
+		return int64(n), fmt.Errorf("failed to write iCalendar with error: %v", err)
+	}
+	return int64(n), nil
+}
+
+// icalFold terminates line with CRLF, folding it into lines of at most 75 octets without splitting UTF-8 characters.
+func icalFold(line string) string {
+	var builder strings.Builder
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space which counts towards the limit
+		limit = icalLineLimit - 1
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+	return builder.String()
+}
+
+func icalEscape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+func icalTitle(code string) string {
+	title := strings.ReplaceAll(code, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+func icalDuration(seconds int) string {
+	return fmt.Sprintf("%dh %02dm", seconds/3600, seconds%3600/60)
+}
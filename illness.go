@@ -0,0 +1,196 @@
+// This file contains an early warning detector for illness or strain.
+//
+// Illness typically shows as a raised body temperature, raised resting heart rate and breathing rate, and suppressed
+// HRV.  Each signal is compared with its threshold, temperature as the deviation reported by DailyReadiness and the
+// others as z-scores against the user's baseline, and the signals over their thresholds are summed into a daily risk
+// score.  Days within a RestMode period are excluded, both from the results and from the baselines.
+
+package go_oura
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// illnessSignalCap caps the score a single signal adds, so one extreme signal alone does not dominate the risk.
+const illnessSignalCap = 2
+
+// IllnessSignal is a signal contributing to an IllnessRisk.
+type IllnessSignal string
+
+const (
+	IllnessSignalTemperature      IllnessSignal = "temperature"
+	IllnessSignalTemperatureTrend IllnessSignal = "temperature_trend"
+	IllnessSignalRestingHeartRate IllnessSignal = "resting_heart_rate"
+	IllnessSignalHrv              IllnessSignal = "hrv"
+	IllnessSignalBreathingRate    IllnessSignal = "breathing_rate"
+)
+
+// IllnessThresholds are the values at which each signal contributes to the risk score.  Temperatures are degrees
+// Celsius above baseline, the others are z-scores, with HRV counted when it is that many deviations below baseline.
+type IllnessThresholds struct {
+	TemperatureDeviation      float64
+	TemperatureTrendDeviation float64
+	RestingHeartRate          float64
+	Hrv                       float64
+	BreathingRate             float64
+
+	// Alert is the risk score at which IllnessRisk.Alert is set.
+	Alert float64
+}
+
+// DefaultIllnessThresholds returns thresholds of 0.5 °C for temperature, 0.3 °C for the temperature trend, 2
+// deviations for the others, and an alert at a score of 2.
+func DefaultIllnessThresholds() IllnessThresholds {
+	return IllnessThresholds{
+		TemperatureDeviation:      0.5,
+		TemperatureTrendDeviation: 0.3,
+		RestingHeartRate:          2,
+		Hrv:                       2,
+		BreathingRate:             2,
+		Alert:                     2,
+	}
+}
+
+// IllnessContribution is a signal over its threshold.  Deviation is in degrees for temperatures and a z-score
+// otherwise, Score is Deviation / Threshold capped at 2.
+type IllnessContribution struct {
+	Signal    IllnessSignal
+	Value     float64
+	Deviation float64
+	Threshold float64
+	Score     float64
+}
+
+// String describes the contribution, for example "resting_heart_rate 2.4 deviations above baseline".
+func (c IllnessContribution) String() string {
+	switch c.Signal {
+	case IllnessSignalTemperature, IllnessSignalTemperatureTrend:
+		return fmt.Sprintf("%s %+.2f °C from baseline", c.Signal, c.Deviation)
+	case IllnessSignalHrv:
+		return fmt.Sprintf("%s %.1f deviations below baseline", c.Signal, -c.Deviation)
+	}
+	return fmt.Sprintf("%s %.1f deviations above baseline", c.Signal, c.Deviation)
+}
+
+// IllnessRisk is the risk for a day.  Signals holds the signals over their thresholds, highest score first.
+// Excluded days fall within a RestMode period and have no score.
+type IllnessRisk struct {
+	Day      Date
+	Score    float64
+	Alert    bool
+	Signals  []IllnessContribution
+	Excluded bool
+}
+
+// Explanation describes the signals contributing to the risk, or is empty when there are none.
+func (r IllnessRisk) Explanation() string {
+	descriptions := make([]string, len(r.Signals))
+	for i, signal := range r.Signals {
+		descriptions[i] = signal.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// IllnessDetector computes a daily IllnessRisk.
+type IllnessDetector struct {
+	Thresholds IllnessThresholds
+	Baseline   *BaselineCalculator
+}
+
+// NewIllnessDetector returns an IllnessDetector with DefaultIllnessThresholds and a 14 day baseline.
+func NewIllnessDetector() *IllnessDetector {
+	return &IllnessDetector{Thresholds: DefaultIllnessThresholds(), Baseline: NewBaselineCalculator(14)}
+}
+
+// Detect returns the IllnessRisk of each of nights, in day order.  Days within restModes are excluded.
+func (d *IllnessDetector) Detect(nights []Night, restModes []RestMode) []IllnessRisk {
+	sorted := slices.Clone(nights)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Day.Before(sorted[j].Day.Time) })
+
+	var included []Night
+	for _, night := range sorted {
+		if !inRestMode(night.Day, restModes) {
+			included = append(included, night)
+		}
+	}
+
+	deviations := make(map[Biomarker]map[string]BaselineDeviation)
+	for biomarker, days := range d.Baseline.Biomarkers(included) {
+		deviations[biomarker] = make(map[string]BaselineDeviation)
+		for _, deviation := range days {
+			deviations[biomarker][deviation.Day.Format("2006-01-02")] = deviation
+		}
+	}
+
+	risks := make([]IllnessRisk, 0, len(sorted))
+	for _, night := range sorted {
+		risk := IllnessRisk{Day: night.Day}
+		if inRestMode(night.Day, restModes) {
+			risk.Excluded = true
+			risks = append(risks, risk)
+			continue
+		}
+
+		if readiness := night.DailyReadiness; readiness != nil {
+			if !readiness.TemperatureDeviationMissing {
+				risk.add(IllnessSignalTemperature, readiness.TemperatureDeviation, readiness.TemperatureDeviation, d.Thresholds.TemperatureDeviation)
+			}
+			risk.add(IllnessSignalTemperatureTrend, readiness.TemperatureTrendDeviation, readiness.TemperatureTrendDeviation, d.Thresholds.TemperatureTrendDeviation)
+		}
+
+		key := night.Day.Format("2006-01-02")
+		if deviation, ok := deviations[BiomarkerRestingHeartRate][key]; ok && deviation.Ok {
+			risk.add(IllnessSignalRestingHeartRate, deviation.Value, deviation.ZScore, d.Thresholds.RestingHeartRate)
+		}
+		if deviation, ok := deviations[BiomarkerHrv][key]; ok && deviation.Ok {
+			risk.add(IllnessSignalHrv, deviation.Value, -deviation.ZScore, d.Thresholds.Hrv)
+		}
+		if deviation, ok := deviations[BiomarkerBreathingRate][key]; ok && deviation.Ok {
+			risk.add(IllnessSignalBreathingRate, deviation.Value, deviation.ZScore, d.Thresholds.BreathingRate)
+		}
+
+		sort.SliceStable(risk.Signals, func(i, j int) bool { return risk.Signals[i].Score > risk.Signals[j].Score })
+		risk.Alert = risk.Score >= d.Thresholds.Alert
+		risks = append(risks, risk)
+	}
+
+	return risks
+}
+
+// add records signal when magnitude, the deviation in the direction of illness, reaches threshold.
+func (r *IllnessRisk) add(signal IllnessSignal, value float64, magnitude float64, threshold float64) {
+	if threshold <= 0 || magnitude < threshold {
+		return
+	}
+
+	deviation := magnitude
+	if signal == IllnessSignalHrv {
+		deviation = -magnitude
+	}
+
+	score := min(magnitude/threshold, illnessSignalCap)
+	r.Score += score
+	r.Signals = append(r.Signals, IllnessContribution{
+		Signal:    signal,
+		Value:     value,
+		Deviation: deviation,
+		Threshold: threshold,
+		Score:     score,
+	})
+}
+
+// inRestMode reports whether day is within any of restModes.  A period without an end day is ongoing.
+func inRestMode(day Date, restModes []RestMode) bool {
+	for _, restMode := range restModes {
+		if day.Before(restMode.StartDay.Time) {
+			continue
+		}
+		if restMode.EndDay.IsZero() || !day.After(restMode.EndDay.Time) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+// This file contains an encoder writing Oura Ring data as InfluxDB line protocol.
+//
+// Interval series such as Sleep.HeartRate become one point per sample with the time of each sample computed as
+// IntervalItems.Timestamp + i * IntervalItems.Interval.  Daily documents become a single point at the document
+// timestamp, or midnight UTC of the day when the document has no timestamp.  Every point is tagged with the user.
+//
+// Line protocol is described at https://docs.influxdata.com/influxdb/v2/reference/syntax/line-protocol/
+
+package go_oura
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InfluxEncoder writes Oura Ring data as InfluxDB line protocol with nanosecond timestamps.
+type InfluxEncoder struct {
+	writer *bufio.Writer
+	// User is written as the user tag on every point.  When empty the tag is omitted.
+	User string
+}
+
+// NewInfluxEncoder returns an InfluxEncoder writing to w and tagging every point with user.
+func NewInfluxEncoder(w io.Writer, user string) *InfluxEncoder {
+	return &InfluxEncoder{
+		writer: bufio.NewWriter(w),
+		User:   user,
+	}
+}
+
+type influxPoint struct {
+	measurement string
+	tags        map[string]string
+	fields      map[string]any
+	time        time.Time
+}
+
+// EncodeHeartRates writes a heart_rate point per sample with a source tag and a bpm field.
+func (e *InfluxEncoder) EncodeHeartRates(items []HeartRate) error {
+	for _, item := range items {
+		e.point(influxPoint{
+			measurement: "heart_rate",
+			tags:        map[string]string{"source": string(item.Source)},
+			fields:      map[string]any{"bpm": item.Bpm},
+			time:        item.Timestamp,
+		})
+	}
+	return e.writer.Flush()
+}
+
+// EncodeSleeps writes a sleep summary point at the bedtime start of each Sleep along with sleep_heart_rate (bpm)
+// and sleep_hrv (hrv) points for each sample of the heart rate and HRV series.  Points are tagged with the sleep
+// type.
+func (e *InfluxEncoder) EncodeSleeps(items []Sleep) error {
+	for _, item := range items {
+		tags := map[string]string{"sleep_type": string(item.Type)}
+
+		e.point(influxPoint{
+			measurement: "sleep",
+			tags:        tags,
+			fields: map[string]any{
+				"average_breath":       item.AverageBreath,
+				"average_heart_rate":   item.AverageHeartRate,
+				"average_hrv":          item.AverageHrv,
+				"awake_time":           item.AwakeTime,
+				"deep_sleep_duration":  item.DeepSleepDuration,
+				"efficiency":           item.Efficiency,
+				"latency":              item.Latency,
+				"light_sleep_duration": item.LightSleepDuration,
+				"lowest_heart_rate":    item.LowestHeartRate,
+				"rem_sleep_duration":   item.RemSleepDuration,
+				"restless_periods":     item.RestlessPeriods,
+				"time_in_bed":          item.TimeInBed,
+				"total_sleep_duration": item.TotalSleepDuration,
+			},
+			time: item.BedtimeStart,
+		})
+
+		e.series("sleep_heart_rate", "bpm", tags, item.HeartRate)
+		e.series("sleep_hrv", "hrv", tags, item.Hrv)
+	}
+	return e.writer.Flush()
+}
+
+// EncodeSessions writes session_heart_rate (bpm), session_hrv (hrv) and session_motion (motion_count) points for
+// each sample of a Session, tagged with the session type.
+func (e *InfluxEncoder) EncodeSessions(items []Session) error {
+	for _, item := range items {
+		tags := map[string]string{"session_type": string(item.Type)}
+
+		e.series("session_heart_rate", "bpm", tags, IntervalItems(item.HeartRateData))
+		e.series("session_hrv", "hrv", tags, IntervalItems(item.HeartRateVariabilityData))
+		e.series("session_motion", "motion_count", tags, IntervalItems(item.MotionCountData))
+	}
+	return e.writer.Flush()
+}
+
+// EncodeDailyActivities writes a daily_activity point per DailyActivity along with an activity_met (met) point
+// for each sample of the MET series.
+func (e *InfluxEncoder) EncodeDailyActivities(items []DailyActivity) error {
+	for _, item := range items {
+		e.point(influxPoint{
+			measurement: "daily_activity",
+			fields: map[string]any{
+				"score":                       item.Score,
+				"active_calories":             item.ActiveCalories,
+				"total_calories":              item.TotalCalories,
+				"steps":                       item.Steps,
+				"equivalent_walking_distance": item.EquivalentWalkingDistance,
+				"high_activity_time":          item.HighActivityTime,
+				"medium_activity_time":        item.MediumActivityTime,
+				"low_activity_time":           item.LowActivityTime,
+				"sedentary_time":              item.SedentaryTime,
+				"resting_time":                item.RestingTime,
+				"non_wear_time":               item.NonWearTime,
+				"average_met_minutes":         item.AverageMetMinutes,
+			},
+			time: influxDayTime(item.Day, item.Timestamp),
+		})
+
+		e.series("activity_met", "met", nil, IntervalItems(item.Met))
+	}
+	return e.writer.Flush()
+}
+
+// EncodeDailyReadinesses writes a daily_readiness point per DailyReadiness with contributors as contributor_*
+// fields.
+func (e *InfluxEncoder) EncodeDailyReadinesses(items []DailyReadiness) error {
+	for _, item := range items {
+		fields := map[string]any{
+			"score":                       item.Score,
+			"temperature_deviation":       item.TemperatureDeviation,
+			"temperature_trend_deviation": item.TemperatureTrendDeviation,
+		}
+		for name, value := range contributorValues(Contributors(item.Contributors)) {
+			fields["contributor_"+name] = value
+		}
+
+		e.point(influxPoint{
+			measurement: "daily_readiness",
+			fields:      fields,
+			time:        influxDayTime(item.Day, item.Timestamp),
+		})
+	}
+	return e.writer.Flush()
+}
+
+// EncodeDailySleeps writes a daily_sleep point per DailySleep with contributors as contributor_* fields.
+func (e *InfluxEncoder) EncodeDailySleeps(items []DailySleep) error {
+	for _, item := range items {
+		e.point(influxPoint{
+			measurement: "daily_sleep",
+			fields: map[string]any{
+				"score":                   item.Score,
+				"contributor_deep_sleep":  item.Contributors.DeepSleep,
+				"contributor_efficiency":  item.Contributors.Efficiency,
+				"contributor_latency":     item.Contributors.Latency,
+				"contributor_rem_sleep":   item.Contributors.RemSleep,
+				"contributor_restfulness": item.Contributors.Restfulness,
+				"contributor_timing":      item.Contributors.Timing,
+				"contributor_total_sleep": item.Contributors.TotalSleep,
+			},
+			time: influxDayTime(item.Day, item.Timestamp),
+		})
+	}
+	return e.writer.Flush()
+}
+
+// EncodeDailySpo2Readings writes a daily_spo2 point per DailySpo2Reading.
+func (e *InfluxEncoder) EncodeDailySpo2Readings(items []DailySpo2Reading) error {
+	for _, item := range items {
+		e.point(influxPoint{
+			measurement: "daily_spo2",
+			fields:      map[string]any{"average": item.Percentage.Average},
+			time:        influxDayTime(item.Day, time.Time{}),
+		})
+	}
+	return e.writer.Flush()
+}
+
+// EncodeDailyStresses writes a daily_stress point per DailyStress.  The day summary is written as a string field.
+func (e *InfluxEncoder) EncodeDailyStresses(items []DailyStress) error {
+	for _, item := range items {
+		fields := map[string]any{
+			"stress_high":   item.StressHigh,
+			"recovery_high": item.RecoveryHigh,
+		}
+		if item.DaySummary != "" {
+			fields["day_summary"] = string(item.DaySummary)
+		}
+
+		e.point(influxPoint{
+			measurement: "daily_stress",
+			fields:      fields,
+			time:        influxDayTime(item.Day, time.Time{}),
+		})
+	}
+	return e.writer.Flush()
+}
+
+// series writes a point per valid sample of items, skipping gaps.
+func (e *InfluxEncoder) series(measurement string, field string, tags map[string]string, items IntervalItems) {
+	for _, sample := range items.ValidSamples() {
+		e.point(influxPoint{
+			measurement: measurement,
+			tags:        tags,
+			fields:      map[string]any{field: sample.Value},
+			time:        sample.Time,
+		})
+	}
+}
+
+func (e *InfluxEncoder) point(p influxPoint) {
+	var line strings.Builder
+	line.WriteString(influxMeasurementEscaper.Replace(p.measurement))
+
+	tags := make(map[string]string, len(p.tags)+1)
+	for key, value := range p.tags {
+		tags[key] = value
+	}
+	if e.User != "" {
+		tags["user"] = e.User
+	}
+	for _, key := range sortedKeys(tags) {
+		if tags[key] == "" {
+			continue
+		}
+		line.WriteString("," + influxTagEscaper.Replace(key) + "=" + influxTagEscaper.Replace(tags[key]))
+	}
+
+	for i, key := range sortedKeys(p.fields) {
+		if i == 0 {
+			line.WriteString(" ")
+		} else {
+			line.WriteString(",")
+		}
+		line.WriteString(influxTagEscaper.Replace(key) + "=" + influxFieldValue(p.fields[key]))
+	}
+
+	line.WriteString(" " + strconv.FormatInt(p.time.UnixNano(), 10) + "\n")
+
+	// Errors are sticky on the bufio.Writer and returned by Flush.
+	_, _ = e.writer.WriteString(line.String())
+}
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	influxStringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+func influxFieldValue(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v) + "i"
+	case int64:
+		return strconv.FormatInt(v, 10) + "i"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return `"` + influxStringEscaper.Replace(v) + `"`
+	default:
+		return `"` + influxStringEscaper.Replace(fmt.Sprint(v)) + `"`
+	}
+}
+
+func influxDayTime(day Date, timestamp time.Time) time.Time {
+	if !timestamp.IsZero() {
+		return timestamp
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
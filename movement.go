@@ -0,0 +1,195 @@
+// This file contains decoding of Sleep.Movement30Sec into a typed movement series.
+//
+// Movement30Sec holds a digit per 30 seconds of the sleep period, starting at BedtimeStart:
+// 1 = no motion, 2 = restless, 3 = tossing and turning, 4 = active.
+
+package go_oura
+
+import (
+	"fmt"
+	"time"
+)
+
+// MovementInterval is the duration covered by each digit of Sleep.Movement30Sec.
+const MovementInterval = 30 * time.Second
+
+// MovementLevel is a level of movement as encoded in Sleep.Movement30Sec.
+type MovementLevel int
+
+const (
+	MovementStill    MovementLevel = 1
+	MovementRestless MovementLevel = 2
+	MovementTossing  MovementLevel = 3
+	MovementActive   MovementLevel = 4
+)
+
+func (l MovementLevel) String() string {
+	switch l {
+	case MovementStill:
+		return "still"
+	case MovementRestless:
+		return "restless"
+	case MovementTossing:
+		return "tossing"
+	case MovementActive:
+		return "active"
+	default:
+		return fmt.Sprintf("MovementLevel(%d)", int(l))
+	}
+}
+
+// MovementSample is the movement level for the 30 seconds starting at Timestamp.
+type MovementSample struct {
+	Timestamp time.Time
+	Level     MovementLevel
+}
+
+// RestlessEpisode is a run of consecutive samples at or above a movement level.  End is exclusive.
+type RestlessEpisode struct {
+	Start time.Time
+	End   time.Time
+	Peak  MovementLevel
+}
+
+// Duration returns the length of the episode.
+func (e RestlessEpisode) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// MovementHour summarizes the samples within a clock hour starting at Start.
+type MovementHour struct {
+	Start        time.Time
+	Samples      int
+	AverageLevel float64
+	Levels       map[MovementLevel]time.Duration
+}
+
+// MovementAlignment is a movement sample with the sleep stage and heart rate at the same time.  Stage is 0 when the
+// hypnogram does not cover the sample.  HeartRateValid is false, and HeartRate 0, when no heart rate was recorded,
+// including gaps in the heart rate series.
+type MovementAlignment struct {
+	MovementSample
+	Stage          SleepStage
+	HeartRate      float64
+	HeartRateValid bool
+}
+
+// Movement is a time ordered movement series.
+type Movement []MovementSample
+
+// DecodeMovement decodes a Movement30Sec string into samples, the first at start.
+func DecodeMovement(movement string, start time.Time) (Movement, error) {
+	samples := make(Movement, 0, len(movement))
+	for i, digit := range movement {
+		level := MovementLevel(digit - '0')
+		if level < MovementStill || level > MovementActive {
+			return nil, fmt.Errorf("invalid movement level %q at index %d", digit, i)
+		}
+		samples = append(samples, MovementSample{Timestamp: start.Add(time.Duration(i) * MovementInterval), Level: level})
+	}
+	return samples, nil
+}
+
+// Movement decodes Movement30Sec anchored at BedtimeStart.
+func (s Sleep) Movement() (Movement, error) {
+	return DecodeMovement(s.Movement30Sec, s.BedtimeStart)
+}
+
+// RestlessEpisodes returns the runs of consecutive samples at level or above lasting at least minimum.
+func (m Movement) RestlessEpisodes(level MovementLevel, minimum time.Duration) []RestlessEpisode {
+	var episodes []RestlessEpisode
+	var current *RestlessEpisode
+
+	closeEpisode := func() {
+		if current != nil && current.Duration() >= minimum {
+			episodes = append(episodes, *current)
+		}
+		current = nil
+	}
+
+	for _, sample := range m {
+		if sample.Level < level {
+			closeEpisode()
+			continue
+		}
+
+		end := sample.Timestamp.Add(MovementInterval)
+		if current == nil {
+			current = &RestlessEpisode{Start: sample.Timestamp, End: end, Peak: sample.Level}
+			continue
+		}
+		current.End = end
+		current.Peak = max(current.Peak, sample.Level)
+	}
+	closeEpisode()
+
+	return episodes
+}
+
+// HourlySummaries groups the samples by clock hour in the location of their timestamps.
+func (m Movement) HourlySummaries() []MovementHour {
+	var hours []MovementHour
+	var total int
+
+	for _, sample := range m {
+		t := sample.Timestamp
+		start := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+
+		last := len(hours) - 1
+		if last < 0 || !hours[last].Start.Equal(start) {
+			if last >= 0 {
+				hours[last].AverageLevel = float64(total) / float64(hours[last].Samples)
+			}
+			hours = append(hours, MovementHour{Start: start, Levels: make(map[MovementLevel]time.Duration)})
+			total = 0
+			last++
+		}
+
+		hours[last].Samples++
+		hours[last].Levels[sample.Level] += MovementInterval
+		total += int(sample.Level)
+	}
+	if last := len(hours) - 1; last >= 0 {
+		hours[last].AverageLevel = float64(total) / float64(hours[last].Samples)
+	}
+
+	return hours
+}
+
+// Align pairs every sample with the stage of hypnogram and the heartRate sample covering its timestamp.
+func (m Movement) Align(hypnogram Hypnogram, heartRate IntervalItems) []MovementAlignment {
+	aligned := make([]MovementAlignment, len(m))
+	segment := 0
+	for i, sample := range m {
+		aligned[i].MovementSample = sample
+
+		for segment < len(hypnogram) && !sample.Timestamp.Before(hypnogram[segment].End) {
+			segment++
+		}
+		if segment < len(hypnogram) && !sample.Timestamp.Before(hypnogram[segment].Start) {
+			aligned[i].Stage = hypnogram[segment].Stage
+		}
+
+		if heartRate.Interval > 0 && !sample.Timestamp.Before(heartRate.Timestamp) {
+			index := int(sample.Timestamp.Sub(heartRate.Timestamp).Seconds() / heartRate.Interval)
+			if index < len(heartRate.Items) && !heartRate.IsGap(index) {
+				aligned[i].HeartRate = heartRate.Items[index]
+				aligned[i].HeartRateValid = true
+			}
+		}
+	}
+	return aligned
+}
+
+// AlignedMovement decodes Movement30Sec and SleepPhase5Min and aligns them with the HeartRate samples of s.
+func (s Sleep) AlignedMovement() ([]MovementAlignment, error) {
+	movement, err := s.Movement()
+	if err != nil {
+		return nil, err
+	}
+	hypnogram, err := s.Hypnogram()
+	if err != nil {
+		return nil, err
+	}
+	return movement.Align(hypnogram, s.HeartRate), nil
+}
@@ -0,0 +1,145 @@
+// This file contains the Night linker which joins the documents describing one night of sleep.
+//
+// The API splits a night across Sleep periods, the DailySleep score, DailyReadiness and SleepTime, linked only by
+// their Day.  The main sleep period of a night is the longest long_sleep period.  A night split by waking has further
+// long_sleep periods, which are kept as split sleeps rather than naps, and every other period is a nap.
+
+package go_oura
+
+import (
+	"sort"
+)
+
+// Night joins the documents for a single day.  Fields are nil when the API has no document for the day.
+type Night struct {
+	Day            Date
+	MainSleep      *Sleep
+	SplitSleeps    []Sleep
+	Naps           []Sleep
+	DailySleep     *DailySleep
+	DailyReadiness *DailyReadiness
+	SleepTime      *SleepTime
+}
+
+// Periods returns the main sleep, split sleeps and naps ordered by Sleep.Period.
+func (n Night) Periods() []Sleep {
+	var periods []Sleep
+	if n.MainSleep != nil {
+		periods = append(periods, *n.MainSleep)
+	}
+	periods = append(periods, n.SplitSleeps...)
+	periods = append(periods, n.Naps...)
+	sortSleepPeriods(periods)
+	return periods
+}
+
+// TotalSleepDuration returns the seconds slept across every period of the night.
+func (n Night) TotalSleepDuration() int {
+	total := 0
+	for _, period := range n.Periods() {
+		total += period.TotalSleepDuration
+	}
+	return total
+}
+
+// LinkNights joins sleeps, dailySleeps, readinesses and sleepTimes by day, returning a Night per day with any
+// document, in day order.  Deleted sleep periods are dropped.
+func LinkNights(sleeps []Sleep, dailySleeps []DailySleep, readinesses []DailyReadiness, sleepTimes []SleepTime) []Night {
+	nights := make(map[string]*Night)
+	night := func(day Date) *Night {
+		key := day.Format("2006-01-02")
+		if nights[key] == nil {
+			nights[key] = &Night{Day: day}
+		}
+		return nights[key]
+	}
+
+	periods := make(map[string][]Sleep)
+	for _, sleep := range sleeps {
+		if sleep.Type == SleepTypeDeleted {
+			continue
+		}
+		key := night(sleep.Day).Day.Format("2006-01-02")
+		periods[key] = append(periods[key], sleep)
+	}
+	for key, daySleeps := range periods {
+		linkSleepPeriods(nights[key], daySleeps)
+	}
+
+	for _, dailySleep := range dailySleeps {
+		dailySleep := dailySleep
+		night(dailySleep.Day).DailySleep = &dailySleep
+	}
+	for _, readiness := range readinesses {
+		readiness := readiness
+		night(readiness.Day).DailyReadiness = &readiness
+	}
+	for _, sleepTime := range sleepTimes {
+		sleepTime := sleepTime
+		night(sleepTime.Day).SleepTime = &sleepTime
+	}
+
+	linked := make([]Night, 0, len(nights))
+	for _, key := range sortedKeys(nights) {
+		linked = append(linked, *nights[key])
+	}
+	return linked
+}
+
+// LinkNight joins the documents of a DaySnapshot with sleepTime, which may be nil, into a Night.
+func LinkNight(snapshot DaySnapshot, sleepTime *SleepTime) Night {
+	night := Night{
+		Day:            snapshot.Day,
+		DailySleep:     snapshot.DailySleep,
+		DailyReadiness: snapshot.DailyReadiness,
+		SleepTime:      sleepTime,
+	}
+
+	var sleeps []Sleep
+	for _, sleep := range snapshot.Sleeps {
+		if sleep.Type != SleepTypeDeleted {
+			sleeps = append(sleeps, sleep)
+		}
+	}
+	linkSleepPeriods(&night, sleeps)
+
+	return night
+}
+
+func linkSleepPeriods(night *Night, sleeps []Sleep) {
+	main := -1
+	for i, sleep := range sleeps {
+		if sleep.Type != SleepTypeLongSleep {
+			continue
+		}
+		if main < 0 || sleep.TimeInBed > sleeps[main].TimeInBed {
+			main = i
+		}
+	}
+
+	night.MainSleep = nil
+	night.SplitSleeps = nil
+	night.Naps = nil
+	for i, sleep := range sleeps {
+		switch {
+		case i == main:
+			sleep := sleep
+			night.MainSleep = &sleep
+		case sleep.Type == SleepTypeLongSleep:
+			night.SplitSleeps = append(night.SplitSleeps, sleep)
+		default:
+			night.Naps = append(night.Naps, sleep)
+		}
+	}
+	sortSleepPeriods(night.SplitSleeps)
+	sortSleepPeriods(night.Naps)
+}
+
+func sortSleepPeriods(sleeps []Sleep) {
+	sort.SliceStable(sleeps, func(i, j int) bool {
+		if sleeps[i].Period != sleeps[j].Period {
+			return sleeps[i].Period < sleeps[j].Period
+		}
+		return sleeps[i].BedtimeStart.Before(sleeps[j].BedtimeStart)
+	})
+}
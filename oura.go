@@ -1,6 +1,7 @@
 package go_oura
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -101,6 +102,24 @@ func (c *Client) NewRequest(apiUrlPart string, params url.Values) (*http.Request
 	return req, nil
 }
 
+// withContext returns a copy of c whose requests are made with ctx, so cancelling ctx also stops requests which are
+// already in flight.
+func (c *Client) withContext(ctx context.Context) *Client {
+	client := *c
+	client.Config.HTTPClient = contextHTTPClient{ctx: ctx, client: c.Config.HTTPClient}
+	return &client
+}
+
+// contextHTTPClient makes every request of client with ctx.
+type contextHTTPClient struct {
+	ctx    context.Context
+	client HTTPClient
+}
+
+func (c contextHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return c.client.Do(req.WithContext(c.ctx))
+}
+
 func (c *Client) Getter(apiUrlPart string, queryParams url.Values) (*[]byte, error) {
 
 	req, err := c.NewRequest(apiUrlPart, queryParams)
@@ -0,0 +1,314 @@
+// This file contains a Prometheus exporter serving the latest Oura Ring scores and metrics.
+//
+// The exporter polls the API on its own schedule and caches the rendered metrics, so scrapes only ever read the
+// cache and never trigger Oura API calls.  Metrics are written in the Prometheus text exposition format.
+
+package go_oura
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMetricsInterval is how often a MetricsExporter polls the API by default.
+const DefaultMetricsInterval = 15 * time.Minute
+
+var metricsResources = []Resource{
+	ResourceDailyActivity,
+	ResourceDailyReadiness,
+	ResourceDailySleep,
+	ResourceDailySpo2,
+	ResourceDailyStress,
+	ResourceSleep,
+}
+
+// MetricsExporter is an http.Handler serving the latest scores for a single user as Prometheus gauges.  Call Run,
+// or Refresh on your own schedule, to populate the cache it serves.
+type MetricsExporter struct {
+	Client *Client
+	// User is the value of the user label on every metric.
+	User string
+	// Interval is how often Run polls the API and defaults to DefaultMetricsInterval.
+	Interval time.Duration
+	// Lookback is the number of days searched for the latest document of each resource.
+	Lookback int
+	// Now returns the current time and defaults to time.Now.
+	Now func() time.Time
+
+	mu          sync.RWMutex
+	latest      map[Resource]any
+	lastRefresh time.Time
+	refreshErr  error
+	body        []byte
+}
+
+// NewMetricsExporter returns a MetricsExporter for client labelling metrics with user.
+func NewMetricsExporter(client *Client, user string) *MetricsExporter {
+	m := &MetricsExporter{
+		Client:   client,
+		User:     user,
+		Interval: DefaultMetricsInterval,
+		Lookback: 7,
+		Now:      time.Now,
+		latest:   make(map[Resource]any),
+	}
+	m.body = m.render()
+	return m
+}
+
+// Run refreshes the metrics immediately and then every Interval until ctx is done.  Refresh failures are reported
+// through the oura_exporter_refresh_success metric rather than stopping Run.
+func (m *MetricsExporter) Run(ctx context.Context) error {
+	_ = m.Refresh(ctx)
+
+	ticker := time.NewTicker(m.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_ = m.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh polls the API for the latest documents and rebuilds the cached metrics.  When a resource fails to load
+// the previously cached document for it is kept.
+func (m *MetricsExporter) Refresh(ctx context.Context) error {
+	now := m.now()
+	start := truncateDay(now).AddDate(0, 0, -m.Lookback)
+	end := truncateDay(now)
+
+	// Requests are made with ctx so cancelling it also stops requests in flight.
+	client := m.Client.withContext(ctx)
+
+	latest := make(map[Resource]any)
+	var errs []error
+	for _, resource := range metricsResources {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		documents, err := client.fetchAllDocuments(resource, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh %s with error: %v", resource, err))
+			continue
+		}
+		if document, ok := latestMetricsDocument(documents); ok {
+			latest[resource] = document
+		}
+	}
+	err := errors.Join(errs...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.latest == nil {
+		m.latest = make(map[Resource]any)
+	}
+	for resource, document := range latest {
+		m.latest[resource] = document
+	}
+	m.lastRefresh = now
+	m.refreshErr = err
+	m.body = m.render()
+
+	return err
+}
+
+// ServeHTTP writes the cached metrics.
+func (m *MetricsExporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.RLock()
+	body := m.body
+	m.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+func (m *MetricsExporter) interval() time.Duration {
+	if m.Interval <= 0 {
+		return DefaultMetricsInterval
+	}
+	return m.Interval
+}
+
+func (m *MetricsExporter) now() time.Time {
+	if m.Now == nil {
+		return time.Now()
+	}
+	return m.Now()
+}
+
+// latestMetricsDocument returns the document with the latest day.  For sleeps only the main sleep periods are
+// considered so a nap never replaces the night's resting heart rate and HRV.
+func latestMetricsDocument(documents []any) (any, bool) {
+	var latest any
+	var latestDay time.Time
+	var latestDuration int
+
+	for _, document := range documents {
+		var day time.Time
+		duration := 0
+
+		switch d := document.(type) {
+		case DailyActivity:
+			day = d.Day.Time
+		case DailyReadiness:
+			day = d.Day.Time
+		case DailySleep:
+			day = d.Day.Time
+		case DailySpo2Reading:
+			day = d.Day.Time
+		case DailyStress:
+			day = d.Day.Time
+		case Sleep:
+			if d.Type != SleepTypeLongSleep {
+				continue
+			}
+			day = d.Day.Time
+			duration = d.TotalSleepDuration
+		default:
+			continue
+		}
+
+		if latest == nil || day.After(latestDay) || (day.Equal(latestDay) && duration > latestDuration) {
+			latest, latestDay, latestDuration = document, day, duration
+		}
+	}
+
+	return latest, latest != nil
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
+
+func (m *MetricsExporter) render() []byte {
+	var families []metricFamily
+	gauge := func(name string, help string, value float64) {
+		families = append(families, metricFamily{name, help, []metricSample{{value: value}}})
+	}
+	contributors := func(name string, help string, values map[string]int) {
+		family := metricFamily{name: name, help: help}
+		for contributor, value := range values {
+			family.samples = append(family.samples, metricSample{
+				labels: map[string]string{"contributor": contributor},
+				value:  float64(value),
+			})
+		}
+		families = append(families, family)
+	}
+
+	if d, ok := m.latest[ResourceDailyReadiness].(DailyReadiness); ok {
+		gauge("oura_readiness_score", "Latest daily readiness score.", float64(d.Score))
+		contributors("oura_readiness_contributor", "Latest daily readiness contributor scores.",
+			contributorValues(Contributors(d.Contributors)))
+		gauge("oura_temperature_deviation_celsius", "Latest body temperature deviation from baseline.",
+			d.TemperatureDeviation)
+		gauge("oura_temperature_trend_deviation_celsius", "Latest body temperature trend deviation.",
+			d.TemperatureTrendDeviation)
+	}
+
+	if d, ok := m.latest[ResourceDailySleep].(DailySleep); ok {
+		gauge("oura_sleep_score", "Latest daily sleep score.", float64(d.Score))
+		contributors("oura_sleep_contributor", "Latest daily sleep contributor scores.", map[string]int{
+			"deep_sleep":  int(d.Contributors.DeepSleep),
+			"efficiency":  int(d.Contributors.Efficiency),
+			"latency":     int(d.Contributors.Latency),
+			"rem_sleep":   int(d.Contributors.RemSleep),
+			"restfulness": int(d.Contributors.Restfulness),
+			"timing":      int(d.Contributors.Timing),
+			"total_sleep": int(d.Contributors.TotalSleep),
+		})
+	}
+
+	if d, ok := m.latest[ResourceDailyActivity].(DailyActivity); ok {
+		gauge("oura_activity_score", "Latest daily activity score.", float64(d.Score))
+		contributors("oura_activity_contributor", "Latest daily activity contributor scores.", map[string]int{
+			"meet_daily_targets": d.Contributors.MeetDailyTargets,
+			"move_every_hour":    d.Contributors.MoveEveryHour,
+			"recovery_time":      d.Contributors.RecoveryTime,
+			"stay_active":        d.Contributors.StayActive,
+			"training_frequency": d.Contributors.TrainingFrequency,
+			"training_volume":    d.Contributors.TrainingVolume,
+		})
+		gauge("oura_steps", "Steps taken on the latest activity day.", float64(d.Steps))
+		gauge("oura_active_calories", "Active calories burned on the latest activity day.", float64(d.ActiveCalories))
+	}
+
+	if d, ok := m.latest[ResourceSleep].(Sleep); ok {
+		gauge("oura_resting_heart_rate_bpm", "Lowest heart rate during the latest main sleep.", float64(d.LowestHeartRate))
+		gauge("oura_hrv_milliseconds", "Average heart rate variability during the latest main sleep.", float64(d.AverageHrv))
+		gauge("oura_breathing_rate", "Average breaths per minute during the latest main sleep.", d.AverageBreath)
+	}
+
+	if d, ok := m.latest[ResourceDailySpo2].(DailySpo2Reading); ok {
+		gauge("oura_spo2_percent", "Latest average blood oxygen saturation.", d.Percentage.Average)
+	}
+
+	if d, ok := m.latest[ResourceDailyStress].(DailyStress); ok {
+		gauge("oura_stress_high_seconds", "Time spent in high stress on the latest day.", float64(d.StressHigh))
+		gauge("oura_recovery_high_seconds", "Time spent in high recovery on the latest day.", float64(d.RecoveryHigh))
+	}
+
+	if !m.lastRefresh.IsZero() {
+		gauge("oura_exporter_last_refresh_timestamp_seconds", "Unix time of the last refresh from the Oura API.",
+			float64(m.lastRefresh.Unix()))
+	}
+	success := 0.0
+	if !m.lastRefresh.IsZero() && m.refreshErr == nil {
+		success = 1
+	}
+	gauge("oura_exporter_refresh_success", "Whether the last refresh from the Oura API succeeded.", success)
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		fmt.Fprintf(&buf, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", family.name)
+
+		sort.Slice(family.samples, func(i, j int) bool {
+			return family.samples[i].labels["contributor"] < family.samples[j].labels["contributor"]
+		})
+		for _, sample := range family.samples {
+			labels := map[string]string{"user": m.User}
+			for name, value := range sample.labels {
+				labels[name] = value
+			}
+			fmt.Fprintf(&buf, "%s%s %s\n", family.name, formatMetricLabels(labels),
+				strconv.FormatFloat(sample.value, 'g', -1, 64))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetricLabels(labels map[string]string) string {
+	names := sortedKeys(labels)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, metricLabelEscaper.Replace(labels[name]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
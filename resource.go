@@ -0,0 +1,161 @@
+// This file contains the Resource type which names each of the Oura Ring document collections along with
+// helpers to fetch a page of any collection without knowing its concrete type.
+
+package go_oura
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// Resource names a collection of documents available from the Oura Ring API.  The value matches the last part of
+// the collection URL, for example "daily_activity" for ActivityUrl.
+type Resource string
+
+const (
+	ResourceDailyActivity     Resource = "daily_activity"
+	ResourceDailyReadiness    Resource = "daily_readiness"
+	ResourceDailySleep        Resource = "daily_sleep"
+	ResourceDailySpo2         Resource = "daily_spo2"
+	ResourceDailyStress       Resource = "daily_stress"
+	ResourceEnhancedTag       Resource = "enhanced_tag"
+	ResourceHeartRate         Resource = "heartrate"
+	ResourcePersonalInfo      Resource = "personal_info"
+	ResourceRestMode          Resource = "rest_mode_period"
+	ResourceRingConfiguration Resource = "ring_configuration"
+	ResourceSession           Resource = "session"
+	ResourceSleep             Resource = "sleep"
+	ResourceSleepTime         Resource = "sleep_time"
+	ResourceWorkout           Resource = "workout"
+)
+
+// Resources returns every Resource known to go_oura.
+func Resources() []Resource {
+	return []Resource{
+		ResourceDailyActivity,
+		ResourceDailyReadiness,
+		ResourceDailySleep,
+		ResourceDailySpo2,
+		ResourceDailyStress,
+		ResourceEnhancedTag,
+		ResourceHeartRate,
+		ResourcePersonalInfo,
+		ResourceRestMode,
+		ResourceRingConfiguration,
+		ResourceSession,
+		ResourceSleep,
+		ResourceSleepTime,
+		ResourceWorkout,
+	}
+}
+
+// Url returns the API url part for the resource, for example ActivityUrl for ResourceDailyActivity.
+func (r Resource) Url() string {
+	return path.Join("/usercollection", string(r))
+}
+
+// resourceFetcher pulls a single page of documents for the days start through end.  A nil next token means there
+// are no further pages.
+type resourceFetcher func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error)
+
+var resourceFetchers = map[Resource]resourceFetcher{
+	ResourceDailyActivity: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetActivities(start, end, nextToken)
+		return anySlice(documents.Items), documents.NextToken, err
+	},
+	ResourceDailyReadiness: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetReadinesses(start, end, nextToken)
+		return anySlice(documents.Items), documents.NextToken, err
+	},
+	ResourceDailySleep: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetDailySleeps(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceDailySpo2: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetSpo2Readings(start, end, nextToken)
+		return anySlice(documents.Items), documents.NextToken, err
+	},
+	ResourceDailyStress: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetStresses(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceEnhancedTag: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetEnhancedTags(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceHeartRate: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		// Heart rates are requested by date time, so cover the whole of the end day.
+		documents, err := c.GetHeartRates(start, end.AddDate(0, 0, 1), nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourcePersonalInfo: func(c *Client, _ time.Time, _ time.Time, _ *string) ([]any, *string, error) {
+		document, err := c.GetPersonalInfo()
+		if err != nil {
+			return nil, nil, err
+		}
+		return []any{document}, nil, nil
+	},
+	ResourceRestMode: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetRestModes(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceRingConfiguration: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetRingConfigurations(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceSession: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetSessions(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceSleep: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetSleeps(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceSleepTime: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetSleepTimes(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+	ResourceWorkout: func(c *Client, start time.Time, end time.Time, nextToken *string) ([]any, *string, error) {
+		documents, err := c.GetWorkouts(start, end, nextToken)
+		return anySlice(documents.Items), optionalToken(documents.NextToken), err
+	},
+}
+
+func anySlice[T any](items []T) []any {
+	documents := make([]any, len(items))
+	for i, item := range items {
+		documents[i] = item
+	}
+	return documents
+}
+
+func optionalToken(nextToken string) *string {
+	if nextToken == "" {
+		return nil
+	}
+	return &nextToken
+}
+
+// fetchAllDocuments pulls every page of documents for resource for the days start through end.
+func (c *Client) fetchAllDocuments(resource Resource, start time.Time, end time.Time) ([]any, error) {
+	fetch, ok := resourceFetchers[resource]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource %s", resource)
+	}
+
+	var documents []any
+	var nextToken *string
+	for {
+		page, next, err := fetch(c, start, end, nextToken)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, page...)
+
+		if next == nil {
+			return documents, nil
+		}
+		nextToken = next
+	}
+}
@@ -0,0 +1,168 @@
+// This file contains the expansion of IntervalItems into timestamped samples along with slicing, resampling and
+// merging of interval series.
+//
+// The API reports missing samples as null, which are recorded in IntervalItems.Gaps when decoding.  A zero value is a
+// real reading, such as a motion count of 0.
+
+package go_oura
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Sample is a single value of an interval series.  Valid is false for gaps, in which case Value is zero.
+type Sample struct {
+	Time  time.Time
+	Value float64
+	Valid bool
+}
+
+// SampleTime returns the time of the i-th item, Timestamp + i * Interval.
+func (ii IntervalItems) SampleTime(i int) time.Time {
+	return ii.Timestamp.Add(time.Duration(float64(i) * ii.Interval * float64(time.Second)))
+}
+
+// Samples returns a timestamped Sample per item.
+func (ii IntervalItems) Samples() []Sample {
+	samples := make([]Sample, len(ii.Items))
+	for i, value := range ii.Items {
+		samples[i] = Sample{Time: ii.SampleTime(i), Value: value, Valid: !ii.IsGap(i)}
+	}
+	return samples
+}
+
+// ValidSamples returns the samples which are not gaps.
+func (ii IntervalItems) ValidSamples() []Sample {
+	samples := make([]Sample, 0, len(ii.Items))
+	for _, sample := range ii.Samples() {
+		if sample.Valid {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// Between returns the items whose sample time is within start inclusive and end exclusive, with Timestamp moved to
+// the first of them.
+func (ii IntervalItems) Between(start time.Time, end time.Time) IntervalItems {
+	first := sort.Search(len(ii.Items), func(i int) bool { return !ii.SampleTime(i).Before(start) })
+	last := sort.Search(len(ii.Items), func(i int) bool { return !ii.SampleTime(i).Before(end) })
+	if last < first {
+		last = first
+	}
+
+	between := IntervalItems{
+		Interval:  ii.Interval,
+		Items:     append([]float64(nil), ii.Items[first:last]...),
+		Timestamp: ii.SampleTime(first),
+	}
+	for i := first; i < last; i++ {
+		if ii.IsGap(i) {
+			between.markGap(i - first)
+		}
+	}
+	return between
+}
+
+// markGap marks the i-th item as missing, allocating Gaps when needed.
+func (ii *IntervalItems) markGap(i int) {
+	if ii.Gaps == nil {
+		ii.Gaps = make([]bool, len(ii.Items))
+	}
+	ii.Items[i] = 0
+	ii.Gaps[i] = true
+}
+
+// Resample returns the series at interval seconds, starting at the same Timestamp.  Each new item is the average of
+// the valid items overlapping it, weighted by the overlap, or a gap when none overlap.
+func (ii IntervalItems) Resample(interval float64) (IntervalItems, error) {
+	if interval <= 0 || ii.Interval <= 0 {
+		return IntervalItems{}, fmt.Errorf("failed to resample with invalid interval %v to %v", ii.Interval, interval)
+	}
+
+	length := int(math.Ceil(float64(len(ii.Items)) * ii.Interval / interval))
+	resampled := IntervalItems{Interval: interval, Items: make([]float64, length), Timestamp: ii.Timestamp}
+
+	for k := range resampled.Items {
+		bucketStart := float64(k) * interval
+		bucketEnd := bucketStart + interval
+
+		first := int(bucketStart / ii.Interval)
+		var sum, weight float64
+		for j := first; j < len(ii.Items) && float64(j)*ii.Interval < bucketEnd; j++ {
+			if ii.IsGap(j) {
+				continue
+			}
+			overlap := math.Min(float64(j+1)*ii.Interval, bucketEnd) - math.Max(float64(j)*ii.Interval, bucketStart)
+			if overlap > 0 {
+				sum += ii.Items[j] * overlap
+				weight += overlap
+			}
+		}
+		if weight > 0 {
+			resampled.Items[k] = sum / weight
+		} else {
+			resampled.markGap(k)
+		}
+	}
+
+	return resampled, nil
+}
+
+// MergeIntervalItems joins series with the same interval into a single series ordered by time.  Time between the
+// series is filled with gaps.  Overlapping series are an error.
+func MergeIntervalItems(series ...IntervalItems) (IntervalItems, error) {
+	if len(series) == 0 {
+		return IntervalItems{}, nil
+	}
+
+	sorted := make([]IntervalItems, len(series))
+	copy(sorted, series)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	merged := IntervalItems{Interval: sorted[0].Interval, Timestamp: sorted[0].Timestamp}
+	if merged.Interval <= 0 {
+		return IntervalItems{}, fmt.Errorf("failed to merge series with invalid interval %v", merged.Interval)
+	}
+
+	var gaps []int
+	for _, s := range sorted {
+		if s.Interval != merged.Interval {
+			return IntervalItems{}, fmt.Errorf("failed to merge series with intervals %v and %v", merged.Interval, s.Interval)
+		}
+
+		offset := int(math.Round(s.Timestamp.Sub(merged.Timestamp).Seconds() / merged.Interval))
+		if offset < len(merged.Items) {
+			return IntervalItems{}, fmt.Errorf("failed to merge series overlapping at %v", s.Timestamp)
+		}
+
+		for i := len(merged.Items); i < offset; i++ {
+			gaps = append(gaps, i)
+		}
+		merged.Items = append(merged.Items, make([]float64, offset-len(merged.Items))...)
+		for i := range s.Items {
+			if s.IsGap(i) {
+				gaps = append(gaps, offset+i)
+			}
+		}
+		merged.Items = append(merged.Items, s.Items...)
+	}
+
+	for _, i := range gaps {
+		merged.markGap(i)
+	}
+	return merged, nil
+}
+
+// Samples returns a timestamped Sample per item.  See IntervalItems.Samples.
+func (s SessionDataItems) Samples() []Sample {
+	return IntervalItems(s).Samples()
+}
+
+// Samples returns a timestamped Sample per item.  See IntervalItems.Samples.
+func (m Met) Samples() []Sample {
+	return IntervalItems(m).Samples()
+}
@@ -27,15 +27,28 @@ type Session struct {
 	Day                      Date             `json:"day"`
 	StartDatetime            time.Time        `json:"start_datetime"`
 	EndDatetime              time.Time        `json:"end_datetime"`
-	Type                     string           `json:"type"`
+	Type                     SessionType      `json:"type"`
 	HeartRateData            SessionDataItems `json:"heart_rate"`
 	HeartRateVariabilityData SessionDataItems `json:"heart_rate_variability"`
-	Mood                     string           `json:"mood"`
+	Mood                     SessionMood      `json:"mood"`
 	MotionCountData          SessionDataItems `json:"motion_count"`
 }
 
 type SessionDataItems IntervalItems
 
+// UnmarshalJSON decodes SessionDataItems as IntervalItems, recording missing items in Gaps.
+func (s *SessionDataItems) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return (*IntervalItems)(s).decodeJSON(data)
+}
+
+// MarshalJSON encodes SessionDataItems as IntervalItems, writing gaps as null.
+func (s SessionDataItems) MarshalJSON() ([]byte, error) {
+	return IntervalItems(s).MarshalJSON()
+}
+
 type sessionBase Session
 type sessionsBase Sessions
 
@@ -50,7 +50,7 @@ type Sleep struct {
 	SleepAlgorithmVersion string         `json:"sleep_algorithm_version"`
 	TimeInBed             int            `json:"time_in_bed"`
 	TotalSleepDuration    int            `json:"total_sleep_duration"`
-	Type                  string         `json:"type"`
+	Type                  SleepType      `json:"type"`
 }
 
 type SleepReadiness struct {
@@ -0,0 +1,213 @@
+// This file contains sleep metrics computed across nights: sleep debt, the Sleep Regularity Index, sleep midpoint
+// and social jetlag, and adherence to the recommended bedtime.
+//
+// Metrics are computed from the nights returned by LinkNights, so any date range of Sleep and SleepTime documents can
+// be used.  Days without a night are skipped rather than counted as no sleep.
+
+package go_oura
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// SleepDebtDay is the sleep debt at the end of a day.  Balance is Slept - Need for the day, and Debt is the
+// accumulated shortfall, which sleeping more than Need pays back but never takes below zero.
+type SleepDebtDay struct {
+	Day     Date
+	Slept   time.Duration
+	Need    time.Duration
+	Balance time.Duration
+	Debt    time.Duration
+}
+
+// SleepDebt returns the sleep debt after each of nights, in day order, given a nightly need.  All periods of a night,
+// naps included, count as sleep.
+func SleepDebt(nights []Night, need time.Duration) []SleepDebtDay {
+	var debt time.Duration
+	days := make([]SleepDebtDay, 0, len(nights))
+	for _, night := range sortNights(nights) {
+		slept := time.Duration(night.TotalSleepDuration()) * time.Second
+		debt = max(0, debt+need-slept)
+		days = append(days, SleepDebtDay{Day: night.Day, Slept: slept, Need: need, Balance: slept - need, Debt: debt})
+	}
+	return days
+}
+
+// Midpoint returns the middle of the main sleep, from sleep onset to final awakening in the hypnogram, or from
+// BedtimeStart to BedtimeEnd when the hypnogram is unavailable.  It is false when the night has no main sleep.
+func (n Night) Midpoint() (time.Time, bool) {
+	if n.MainSleep == nil {
+		return time.Time{}, false
+	}
+
+	start, end := n.MainSleep.BedtimeStart, n.MainSleep.BedtimeEnd
+	if hypnogram, err := n.MainSleep.Hypnogram(); err == nil {
+		onset, ok := hypnogram.SleepOnset()
+		if ok {
+			start = onset
+			end, _ = hypnogram.FinalAwakening()
+		}
+	}
+
+	return start.Add(end.Sub(start) / 2), true
+}
+
+// SocialJetlag compares the sleep midpoint of workdays with that of free days, nights ending on a Saturday or
+// Sunday.  Midpoints are the time since local midnight, negative before midnight.  Jetlag is the free day midpoint
+// minus the workday midpoint.
+type SocialJetlag struct {
+	WorkdayMidpoint time.Duration
+	FreeDayMidpoint time.Duration
+	Jetlag          time.Duration
+	WorkdayNights   int
+	FreeDayNights   int
+}
+
+// ComputeSocialJetlag returns the SocialJetlag of nights.  It is false unless there is at least one workday and one
+// free day night with a main sleep.
+func ComputeSocialJetlag(nights []Night) (SocialJetlag, bool) {
+	var jetlag SocialJetlag
+	var workdays, freeDays time.Duration
+	for _, night := range nights {
+		midpoint, ok := night.Midpoint()
+		if !ok {
+			continue
+		}
+
+		// Midpoints are measured from midnight within 12 hours either side, so a night spanning midnight averages
+		// correctly.
+		clock := midpoint.Sub(time.Date(midpoint.Year(), midpoint.Month(), midpoint.Day(), 0, 0, 0, 0, midpoint.Location()))
+		if clock >= 12*time.Hour {
+			clock -= 24 * time.Hour
+		}
+
+		switch night.Day.Weekday() {
+		case time.Saturday, time.Sunday:
+			freeDays += clock
+			jetlag.FreeDayNights++
+		default:
+			workdays += clock
+			jetlag.WorkdayNights++
+		}
+	}
+
+	if jetlag.WorkdayNights == 0 || jetlag.FreeDayNights == 0 {
+		return SocialJetlag{}, false
+	}
+
+	jetlag.WorkdayMidpoint = workdays / time.Duration(jetlag.WorkdayNights)
+	jetlag.FreeDayMidpoint = freeDays / time.Duration(jetlag.FreeDayNights)
+	jetlag.Jetlag = jetlag.FreeDayMidpoint - jetlag.WorkdayMidpoint
+	return jetlag, true
+}
+
+// SleepRegularityIndex returns the Sleep Regularity Index of nights: the probability of being in the same state,
+// asleep or awake, at any two times 24 hours apart, scaled from -100 to 100.  Each night covers the 24 hours from
+// noon the day before its Day, with every sleep period decoded from its hypnogram and the rest of the time counted
+// as awake.  Only nights on consecutive days are compared, and the result is false when there are none.
+func SleepRegularityIndex(nights []Night) (float64, bool) {
+	epochs := int(24 * time.Hour / SleepPhaseInterval)
+
+	var agree, total int
+	var previous []bool
+	var previousDay Date
+	for _, night := range sortNights(nights) {
+		current, ok := night.asleepEpochs(epochs)
+		if !ok {
+			previous = nil
+			continue
+		}
+
+		if previous != nil && night.Day.Equal(previousDay.AddDate(0, 0, 1)) {
+			for i := range current {
+				if current[i] == previous[i] {
+					agree++
+				}
+				total++
+			}
+		}
+		previous, previousDay = current, night.Day
+	}
+
+	if total == 0 {
+		return 0, false
+	}
+	return -100 + 200*float64(agree)/float64(total), true
+}
+
+// asleepEpochs returns whether the user was asleep in each 5 minute epoch from noon the day before the night's
+// Day.  It is false when the night has no main sleep.
+func (n Night) asleepEpochs(epochs int) ([]bool, bool) {
+	if n.MainSleep == nil {
+		return nil, false
+	}
+
+	start := n.Day.MidnightIn(n.MainSleep.BedtimeStart.Location()).Add(-12 * time.Hour)
+	asleep := make([]bool, epochs)
+	for _, period := range n.Periods() {
+		hypnogram, err := period.Hypnogram()
+		if err != nil {
+			continue
+		}
+
+		for _, segment := range hypnogram {
+			if segment.Stage == SleepStageAwake {
+				continue
+			}
+			first := max(0, int(segment.Start.Sub(start)/SleepPhaseInterval))
+			last := min(epochs, int((segment.End.Sub(start)+SleepPhaseInterval-1)/SleepPhaseInterval))
+			for i := first; i < last; i++ {
+				asleep[i] = true
+			}
+		}
+	}
+
+	return asleep, true
+}
+
+// BedtimeAdherence compares the start of a night's main sleep with the recommended bedtime window.  Offset is zero
+// within the window, negative when going to bed before it and positive after it.
+type BedtimeAdherence struct {
+	Day         Date
+	Bedtime     time.Time
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Offset      time.Duration
+	Within      bool
+}
+
+// BedtimeAdherences returns, in day order, the BedtimeAdherence of each of nights with both a main sleep and a
+// recommended bedtime window.
+func BedtimeAdherences(nights []Night) []BedtimeAdherence {
+	var adherences []BedtimeAdherence
+	for _, night := range sortNights(nights) {
+		if night.MainSleep == nil || night.SleepTime == nil {
+			continue
+		}
+		windowStart, windowEnd, ok := night.SleepTime.BedtimeWindow()
+		if !ok {
+			continue
+		}
+
+		bedtime := night.MainSleep.BedtimeStart
+		adherence := BedtimeAdherence{Day: night.Day, Bedtime: bedtime, WindowStart: windowStart, WindowEnd: windowEnd}
+		switch {
+		case bedtime.Before(windowStart):
+			adherence.Offset = bedtime.Sub(windowStart)
+		case bedtime.After(windowEnd):
+			adherence.Offset = bedtime.Sub(windowEnd)
+		default:
+			adherence.Within = true
+		}
+		adherences = append(adherences, adherence)
+	}
+	return adherences
+}
+
+func sortNights(nights []Night) []Night {
+	sorted := slices.Clone(nights)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Day.Before(sorted[j].Day.Time) })
+	return sorted
+}
@@ -24,19 +24,67 @@ type SleepTimes struct {
 // SleepTime stores specifics for a single day optimal bedtime window
 // JSON described at https://cloud.ouraring.com/v2/docs#operation/Single_sleep_time_Document_v2_usercollection_sleep_time__document_id__get
 type SleepTime struct {
-	ID             string          `json:"id"`
-	Day            Date            `json:"day"`
-	OptimalBedtime *OptimalBedtime `json:"optimal_bedtime"`
-	Recommendation string          `json:"recommendation"`
-	Status         string          `json:"status"`
+	ID             string                  `json:"id"`
+	Day            Date                    `json:"day"`
+	OptimalBedtime *OptimalBedtime         `json:"optimal_bedtime"`
+	Recommendation SleepTimeRecommendation `json:"recommendation"`
+	Status         SleepTimeStatus         `json:"status"`
 }
 
+// OptimalBedtime describes the recommended bedtime window.  DayTz is the offset from UTC in seconds of the user's
+// time zone and StartOffset & EndOffset are seconds relative to midnight of the SleepTime day in that zone, negative
+// when the window starts the evening before.
 type OptimalBedtime struct {
 	DayTz       int `json:"day_tz"`
 	EndOffset   int `json:"end_offset"`
 	StartOffset int `json:"start_offset"`
 }
 
+// SleepTimeRecommendation is the recommendation given with a SleepTime.
+type SleepTimeRecommendation string
+
+const (
+	SleepTimeImproveEfficiency    SleepTimeRecommendation = "improve_efficiency"
+	SleepTimeEarlierBedtime       SleepTimeRecommendation = "earlier_bedtime"
+	SleepTimeLaterBedtime         SleepTimeRecommendation = "later_bedtime"
+	SleepTimeEarlierWakeUpTime    SleepTimeRecommendation = "earlier_wake_up_time"
+	SleepTimeLaterWakeUpTime      SleepTimeRecommendation = "later_wake_up_time"
+	SleepTimeFollowOptimalBedtime SleepTimeRecommendation = "follow_optimal_bedtime"
+)
+
+// SleepTimeStatus describes how the SleepTime recommendation was reached.
+type SleepTimeStatus string
+
+const (
+	SleepTimeNotEnoughNights       SleepTimeStatus = "not_enough_nights"
+	SleepTimeNotEnoughRecentNights SleepTimeStatus = "not_enough_recent_nights"
+	SleepTimeBadSleepQuality       SleepTimeStatus = "bad_sleep_quality"
+	SleepTimeOnlyRecommendedFound  SleepTimeStatus = "only_recommended_found"
+	SleepTimeOptimalFound          SleepTimeStatus = "optimal_found"
+)
+
+// Location returns the fixed zone described by DayTz.
+func (ob OptimalBedtime) Location() *time.Location {
+	return time.FixedZone("", ob.DayTz)
+}
+
+// Window returns the start and end of the bedtime window for day, in the zone described by DayTz.
+func (ob OptimalBedtime) Window(day Date) (time.Time, time.Time) {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, ob.Location())
+	return midnight.Add(time.Duration(ob.StartOffset) * time.Second), midnight.Add(time.Duration(ob.EndOffset) * time.Second)
+}
+
+// BedtimeWindow returns the start and end of the recommended bedtime window.  The last value is false when the
+// SleepTime has no OptimalBedtime, for example when Status is not_enough_nights.
+func (st SleepTime) BedtimeWindow() (time.Time, time.Time, bool) {
+	if st.OptimalBedtime == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start, end := st.OptimalBedtime.Window(st.Day)
+	return start, end, true
+}
+
 type sleepTimeBase SleepTime
 type sleepTimesBase SleepTimes
 
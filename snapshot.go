@@ -0,0 +1,206 @@
+// This file contains DaySnapshot, every daily document and the documents recorded during a day fetched together.
+//
+// Resources are fetched concurrently, at most snapshotParallelism at a time, and joined by day.  A resource which
+// fails to load is reported on each snapshot rather than failing the whole fetch.
+
+package go_oura
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// snapshotParallelism bounds the number of concurrent requests made for a snapshot.
+const snapshotParallelism = 4
+
+// snapshotResources are the resources fetched for a DaySnapshot.
+var snapshotResources = []Resource{
+	ResourceDailySleep,
+	ResourceDailyReadiness,
+	ResourceDailyActivity,
+	ResourceDailySpo2,
+	ResourceDailyStress,
+	ResourceSleep,
+	ResourceWorkout,
+	ResourceSession,
+	ResourceEnhancedTag,
+}
+
+// DaySnapshot holds the documents for a single day.  Daily documents are nil when the API has none for the day.
+type DaySnapshot struct {
+	Day            Date
+	DailySleep     *DailySleep
+	DailyReadiness *DailyReadiness
+	DailyActivity  *DailyActivity
+	DailySpo2      *DailySpo2Reading
+	DailyStress    *DailyStress
+	Sleeps         []Sleep
+	Workouts       []Workout
+	Sessions       []Session
+	EnhancedTags   []EnhancedTag
+
+	// Errors holds the resources which failed to load, whose documents are missing from the snapshot.
+	Errors map[Resource]error
+}
+
+// Complete reports whether every resource loaded.
+func (s DaySnapshot) Complete() bool {
+	return len(s.Errors) == 0
+}
+
+// GetDay fetches the DaySnapshot for day.  Only a cancelled ctx is returned as an error, failures of individual
+// resources are reported in DaySnapshot.Errors.
+func (c *Client) GetDay(ctx context.Context, day Date) (DaySnapshot, error) {
+	snapshots, err := c.GetDays(ctx, day, day)
+	if err != nil {
+		return DaySnapshot{}, err
+	}
+	return snapshots[0], nil
+}
+
+// GetDays fetches a DaySnapshot for every day from start through end, in day order.  Only a cancelled ctx is
+// returned as an error, failures of individual resources are reported in DaySnapshot.Errors.  Every request is made
+// with ctx, so cancelling it also stops requests in flight.
+func (c *Client) GetDays(ctx context.Context, start Date, end Date) ([]DaySnapshot, error) {
+	client := c.withContext(ctx)
+
+	type result struct {
+		resource  Resource
+		documents []any
+		err       error
+	}
+
+	results := make([]result, len(snapshotResources))
+	semaphore := make(chan struct{}, snapshotParallelism)
+	var wg sync.WaitGroup
+
+	for i, resource := range snapshotResources {
+		wg.Add(1)
+		go func(i int, resource Resource) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				results[i] = result{resource: resource, err: err}
+				return
+			}
+
+			// The API end date is exclusive for some resources, so fetch a day more and drop it when joining.
+			documents, err := client.fetchAllDocuments(resource, start.Time, end.AddDate(0, 0, 1))
+			results[i] = result{resource: resource, documents: documents, err: err}
+		}(i, resource)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var snapshots []DaySnapshot
+	byDay := make(map[string]*DaySnapshot)
+	for day := start.Time; !day.After(end.Time); day = day.AddDate(0, 0, 1) {
+		snapshots = append(snapshots, DaySnapshot{Day: Date{Time: day}})
+	}
+	for i := range snapshots {
+		byDay[snapshots[i].Day.Format("2006-01-02")] = &snapshots[i]
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			for i := range snapshots {
+				if snapshots[i].Errors == nil {
+					snapshots[i].Errors = make(map[Resource]error)
+				}
+				snapshots[i].Errors[r.resource] = r.err
+			}
+			continue
+		}
+
+		for _, document := range r.documents {
+			addToSnapshots(byDay, document)
+		}
+	}
+
+	for i := range snapshots {
+		sortSnapshot(&snapshots[i])
+	}
+
+	return snapshots, nil
+}
+
+func addToSnapshots(byDay map[string]*DaySnapshot, document any) {
+	snapshot := func(day Date) *DaySnapshot {
+		return byDay[day.Format("2006-01-02")]
+	}
+
+	switch d := document.(type) {
+	case DailySleep:
+		if s := snapshot(d.Day); s != nil {
+			s.DailySleep = &d
+		}
+	case DailyReadiness:
+		if s := snapshot(d.Day); s != nil {
+			s.DailyReadiness = &d
+		}
+	case DailyActivity:
+		if s := snapshot(d.Day); s != nil {
+			s.DailyActivity = &d
+		}
+	case DailySpo2Reading:
+		if s := snapshot(d.Day); s != nil {
+			s.DailySpo2 = &d
+		}
+	case DailyStress:
+		if s := snapshot(d.Day); s != nil {
+			s.DailyStress = &d
+		}
+	case Sleep:
+		if s := snapshot(d.Day); s != nil {
+			s.Sleeps = append(s.Sleeps, d)
+		}
+	case Workout:
+		if s := snapshot(d.Day); s != nil {
+			s.Workouts = append(s.Workouts, d)
+		}
+	case Session:
+		if s := snapshot(d.Day); s != nil {
+			s.Sessions = append(s.Sessions, d)
+		}
+	case EnhancedTag:
+		// A tag belongs to every day from its start day through its end day.
+		if d.StartDay == nil {
+			return
+		}
+		last := d.StartDay.Time
+		if d.EndDay != nil && d.EndDay.After(last) {
+			last = d.EndDay.Time
+		}
+		for day := d.StartDay.Time; !day.After(last); day = day.AddDate(0, 0, 1) {
+			if s := snapshot(Date{Time: day}); s != nil {
+				s.EnhancedTags = append(s.EnhancedTags, d)
+			}
+		}
+	}
+}
+
+func sortSnapshot(s *DaySnapshot) {
+	sort.SliceStable(s.Sleeps, func(i, j int) bool { return s.Sleeps[i].BedtimeStart.Before(s.Sleeps[j].BedtimeStart) })
+	sort.SliceStable(s.Workouts, func(i, j int) bool { return s.Workouts[i].StartDatetime.Before(s.Workouts[j].StartDatetime) })
+	sort.SliceStable(s.Sessions, func(i, j int) bool { return s.Sessions[i].StartDatetime.Before(s.Sessions[j].StartDatetime) })
+	sort.SliceStable(s.EnhancedTags, func(i, j int) bool {
+		return tagSortTime(s.EnhancedTags[i]).Before(tagSortTime(s.EnhancedTags[j]))
+	})
+}
+
+func tagSortTime(tag EnhancedTag) time.Time {
+	if tag.StartTime != nil {
+		return *tag.StartTime
+	}
+	return tag.StartDay.Time
+}
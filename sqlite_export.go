@@ -0,0 +1,663 @@
+// This file contains an exporter which writes Oura Ring documents into a SQLite database with a normalized schema.
+//
+// go_oura does not depend on a SQLite driver.  Open the database with the driver of your choice, for example
+// modernc.org/sqlite or github.com/mattn/go-sqlite3, and pass the *sql.DB to NewSQLiteExporter.
+//
+// Every resource has its own table keyed by document ID, heart rates are keyed by timestamp and source.
+// Contributors, rest mode episodes and interval series samples are held in child tables which are replaced whenever
+// their parent document is exported again, so re-exporting the same documents is idempotent.
+//
+// Timestamps are stored in UTC as YYYY-MM-DDTHH:MM:SS.SSSZ, the format of SQLite's own strftime, so they order and
+// compare correctly as TEXT and work with SQLite's date and time functions.  Days are stored as YYYY-MM-DD.
+
+package go_oura
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqliteMigrations holds the schema changes in order.  The version of a migration is its index plus one.  Never
+// change an existing migration, add a new one instead.
+var sqliteMigrations = [][]string{
+	{
+		`CREATE TABLE daily_activity (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			timestamp TEXT,
+			score INTEGER,
+			active_calories INTEGER,
+			average_met_minutes REAL,
+			equivalent_walking_distance INTEGER,
+			high_activity_met_minutes INTEGER,
+			high_activity_time INTEGER,
+			inactivity_alerts INTEGER,
+			low_activity_met_minutes INTEGER,
+			low_activity_time INTEGER,
+			medium_activity_met_minutes INTEGER,
+			medium_activity_time INTEGER,
+			meters_to_target INTEGER,
+			non_wear_time INTEGER,
+			resting_time INTEGER,
+			sedentary_met_minutes INTEGER,
+			sedentary_time INTEGER,
+			steps INTEGER,
+			target_calories INTEGER,
+			target_meters INTEGER,
+			total_calories INTEGER,
+			class_5_min TEXT
+		)`,
+		`CREATE INDEX daily_activity_day ON daily_activity (day)`,
+		`CREATE TABLE daily_activity_contributor (
+			daily_activity_id TEXT NOT NULL REFERENCES daily_activity (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			value INTEGER,
+			PRIMARY KEY (daily_activity_id, name)
+		)`,
+		`CREATE TABLE daily_activity_sample (
+			daily_activity_id TEXT NOT NULL REFERENCES daily_activity (id) ON DELETE CASCADE,
+			series TEXT NOT NULL,
+			sample_index INTEGER NOT NULL,
+			timestamp TEXT,
+			value REAL,
+			PRIMARY KEY (daily_activity_id, series, sample_index)
+		)`,
+		`CREATE TABLE daily_readiness (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			timestamp TEXT,
+			score INTEGER,
+			temperature_deviation REAL,
+			temperature_trend_deviation REAL
+		)`,
+		`CREATE INDEX daily_readiness_day ON daily_readiness (day)`,
+		`CREATE TABLE daily_readiness_contributor (
+			daily_readiness_id TEXT NOT NULL REFERENCES daily_readiness (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			value INTEGER,
+			PRIMARY KEY (daily_readiness_id, name)
+		)`,
+		`CREATE TABLE daily_sleep (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			timestamp TEXT,
+			score INTEGER
+		)`,
+		`CREATE INDEX daily_sleep_day ON daily_sleep (day)`,
+		`CREATE TABLE daily_sleep_contributor (
+			daily_sleep_id TEXT NOT NULL REFERENCES daily_sleep (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			value INTEGER,
+			PRIMARY KEY (daily_sleep_id, name)
+		)`,
+		`CREATE TABLE daily_spo2 (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			spo2_percentage_average REAL
+		)`,
+		`CREATE TABLE daily_stress (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			stress_high INTEGER,
+			recovery_high INTEGER,
+			day_summary TEXT
+		)`,
+		`CREATE TABLE enhanced_tag (
+			id TEXT PRIMARY KEY,
+			tag_type_code TEXT,
+			start_time TEXT,
+			end_time TEXT,
+			start_day TEXT,
+			end_day TEXT,
+			comment TEXT
+		)`,
+		`CREATE TABLE heart_rate (
+			timestamp TEXT NOT NULL,
+			bpm INTEGER,
+			source TEXT NOT NULL,
+			PRIMARY KEY (timestamp, source)
+		)`,
+		`CREATE TABLE personal_info (
+			id TEXT PRIMARY KEY,
+			age INTEGER,
+			height REAL,
+			weight REAL,
+			biological_sex TEXT,
+			email TEXT
+		)`,
+		`CREATE TABLE rest_mode_period (
+			id TEXT PRIMARY KEY,
+			start_day TEXT,
+			end_day TEXT,
+			start_time TEXT,
+			end_time TEXT
+		)`,
+		`CREATE TABLE rest_mode_episode (
+			rest_mode_period_id TEXT NOT NULL REFERENCES rest_mode_period (id) ON DELETE CASCADE,
+			episode_index INTEGER NOT NULL,
+			timestamp TEXT,
+			PRIMARY KEY (rest_mode_period_id, episode_index)
+		)`,
+		`CREATE TABLE rest_mode_episode_tag (
+			rest_mode_period_id TEXT NOT NULL REFERENCES rest_mode_period (id) ON DELETE CASCADE,
+			episode_index INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (rest_mode_period_id, episode_index, tag)
+		)`,
+		`CREATE TABLE ring_configuration (
+			id TEXT PRIMARY KEY,
+			color TEXT,
+			design TEXT,
+			firmware_version TEXT,
+			hardware_type TEXT,
+			set_up_at TEXT,
+			size INTEGER
+		)`,
+		`CREATE TABLE session (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			start_datetime TEXT,
+			end_datetime TEXT,
+			type TEXT,
+			mood TEXT
+		)`,
+		`CREATE INDEX session_day ON session (day)`,
+		`CREATE TABLE session_sample (
+			session_id TEXT NOT NULL REFERENCES session (id) ON DELETE CASCADE,
+			series TEXT NOT NULL,
+			sample_index INTEGER NOT NULL,
+			timestamp TEXT,
+			value REAL,
+			PRIMARY KEY (session_id, series, sample_index)
+		)`,
+		`CREATE TABLE sleep (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			type TEXT,
+			period INTEGER,
+			bedtime_start TEXT,
+			bedtime_end TEXT,
+			average_breath REAL,
+			average_heart_rate REAL,
+			average_hrv INTEGER,
+			awake_time INTEGER,
+			deep_sleep_duration INTEGER,
+			efficiency INTEGER,
+			latency INTEGER,
+			light_sleep_duration INTEGER,
+			low_battery_alert INTEGER,
+			lowest_heart_rate INTEGER,
+			movement_30_sec TEXT,
+			readiness_score INTEGER,
+			readiness_temperature_deviation REAL,
+			readiness_temperature_trend_deviation REAL,
+			readiness_score_delta INTEGER,
+			rem_sleep_duration INTEGER,
+			restless_periods INTEGER,
+			sleep_phase_5_min TEXT,
+			sleep_score_delta INTEGER,
+			sleep_algorithm_version TEXT,
+			time_in_bed INTEGER,
+			total_sleep_duration INTEGER
+		)`,
+		`CREATE INDEX sleep_day ON sleep (day)`,
+		`CREATE TABLE sleep_readiness_contributor (
+			sleep_id TEXT NOT NULL REFERENCES sleep (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			value INTEGER,
+			PRIMARY KEY (sleep_id, name)
+		)`,
+		`CREATE TABLE sleep_sample (
+			sleep_id TEXT NOT NULL REFERENCES sleep (id) ON DELETE CASCADE,
+			series TEXT NOT NULL,
+			sample_index INTEGER NOT NULL,
+			timestamp TEXT,
+			value REAL,
+			PRIMARY KEY (sleep_id, series, sample_index)
+		)`,
+		`CREATE TABLE sleep_time (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			optimal_bedtime_day_tz INTEGER,
+			optimal_bedtime_start_offset INTEGER,
+			optimal_bedtime_end_offset INTEGER,
+			recommendation TEXT,
+			status TEXT
+		)`,
+		`CREATE TABLE workout (
+			id TEXT PRIMARY KEY,
+			day TEXT NOT NULL,
+			activity TEXT,
+			calories REAL,
+			distance REAL,
+			start_datetime TEXT,
+			end_datetime TEXT,
+			intensity TEXT,
+			label TEXT,
+			source TEXT
+		)`,
+		`CREATE INDEX workout_day ON workout (day)`,
+	},
+}
+
+// sqliteTimeLayout is the layout of timestamps, which are always stored in UTC.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// SQLiteExporter writes go_oura documents into a SQLite database.
+type SQLiteExporter struct {
+	db       *sql.DB
+	migrated bool
+}
+
+// NewSQLiteExporter returns a SQLiteExporter writing to db.
+func NewSQLiteExporter(db *sql.DB) *SQLiteExporter {
+	return &SQLiteExporter{db: db}
+}
+
+// SchemaVersion is the schema version the exporter migrates the database to.
+func (e *SQLiteExporter) SchemaVersion() int {
+	return len(sqliteMigrations)
+}
+
+// Migrate brings the database schema up to date, applying only the migrations which have not run before.
+func (e *SQLiteExporter) Migrate(ctx context.Context) error {
+	_, err := e.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table with error: %v", err)
+	}
+
+	var version int
+	err = e.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version with error: %v", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := e.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d with error: %v", i+1, err)
+		}
+
+		for _, statement := range sqliteMigrations[i] {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("failed to apply migration %d with error: %v", i+1, err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			i+1, sqliteTime(time.Now()))
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to record migration %d with error: %v", i+1, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d with error: %v", i+1, err)
+		}
+	}
+
+	e.migrated = true
+	return nil
+}
+
+// Export writes documents to the database in a single transaction, migrating the schema first if needed.  It
+// accepts the same document and list types as Store.Upsert.  Documents which were exported before are replaced.
+func (e *SQLiteExporter) Export(ctx context.Context, documents ...any) error {
+	if !e.migrated {
+		if err := e.Migrate(ctx); err != nil {
+			return err
+		}
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin export with error: %v", err)
+	}
+
+	for _, document := range documents {
+		for _, item := range expandDocuments(document) {
+			if err := exportSQLiteDocument(ctx, tx, item); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit export with error: %v", err)
+	}
+
+	return nil
+}
+
+// WriteDocuments exports documents, allowing a SQLiteExporter to be used as a SyncSink.
+func (e *SQLiteExporter) WriteDocuments(ctx context.Context, _ Resource, documents []any) error {
+	return e.Export(ctx, documents...)
+}
+
+func exportSQLiteDocument(ctx context.Context, tx *sql.Tx, document any) error {
+	var err error
+
+	switch d := document.(type) {
+	case DailyActivity:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO daily_activity (id, day, timestamp, score, active_calories, average_met_minutes,
+				equivalent_walking_distance, high_activity_met_minutes, high_activity_time, inactivity_alerts,
+				low_activity_met_minutes, low_activity_time, medium_activity_met_minutes, medium_activity_time,
+				meters_to_target, non_wear_time, resting_time, sedentary_met_minutes, sedentary_time, steps,
+				target_calories, target_meters, total_calories, class_5_min)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), sqliteTime(d.Timestamp), d.Score, d.ActiveCalories, d.AverageMetMinutes,
+			d.EquivalentWalkingDistance, d.HighActivityMetMinutes, d.HighActivityTime, d.InactivityAlerts,
+			d.LowActivityMetMinutes, d.LowActivityTime, d.MediumActivityMetMinutes, d.MediumActivityTime,
+			d.MetersToTarget, d.NonWearTime, d.RestingTime, d.SedentaryMetMinutes, d.SedentaryTime, d.Steps,
+			d.TargetCalories, d.TargetMeters, d.TotalCalories, d.Class5Min,
+		)
+		if err == nil {
+			err = replaceSQLiteContributors(ctx, tx, "daily_activity_contributor", "daily_activity_id", d.ID, map[string]int{
+				"meet_daily_targets": d.Contributors.MeetDailyTargets,
+				"move_every_hour":    d.Contributors.MoveEveryHour,
+				"recovery_time":      d.Contributors.RecoveryTime,
+				"stay_active":        d.Contributors.StayActive,
+				"training_frequency": d.Contributors.TrainingFrequency,
+				"training_volume":    d.Contributors.TrainingVolume,
+			})
+		}
+		if err == nil {
+			err = replaceSQLiteSamples(ctx, tx, "daily_activity_sample", "daily_activity_id", d.ID, map[string]IntervalItems{
+				"met": IntervalItems(d.Met),
+			})
+		}
+	case DailyReadiness:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.Id)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO daily_readiness (id, day, timestamp, score, temperature_deviation,
+				temperature_trend_deviation)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			d.Id, sqliteDay(d.Day), sqliteTime(d.Timestamp), d.Score, d.TemperatureDeviation, d.TemperatureTrendDeviation,
+		)
+		if err == nil {
+			err = replaceSQLiteContributors(ctx, tx, "daily_readiness_contributor", "daily_readiness_id", d.Id,
+				contributorValues(Contributors(d.Contributors)))
+		}
+	case DailySleep:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO daily_sleep (id, day, timestamp, score) VALUES (?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), sqliteTime(d.Timestamp), d.Score,
+		)
+		if err == nil {
+			err = replaceSQLiteContributors(ctx, tx, "daily_sleep_contributor", "daily_sleep_id", d.ID, map[string]int{
+				"deep_sleep":  int(d.Contributors.DeepSleep),
+				"efficiency":  int(d.Contributors.Efficiency),
+				"latency":     int(d.Contributors.Latency),
+				"rem_sleep":   int(d.Contributors.RemSleep),
+				"restfulness": int(d.Contributors.Restfulness),
+				"timing":      int(d.Contributors.Timing),
+				"total_sleep": int(d.Contributors.TotalSleep),
+			})
+		}
+	case DailySpo2Reading:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO daily_spo2 (id, day, spo2_percentage_average) VALUES (?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), d.Percentage.Average,
+		)
+	case DailyStress:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO daily_stress (id, day, stress_high, recovery_high, day_summary)
+			VALUES (?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), d.StressHigh, d.RecoveryHigh, string(d.DaySummary),
+		)
+	case EnhancedTag:
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO enhanced_tag (id, tag_type_code, start_time, end_time, start_day, end_day, comment)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, d.TagTypeCode, sqliteTimePtr(d.StartTime), sqliteTimePtr(d.EndTime), sqliteDayPtr(d.StartDay),
+			sqliteDayPtr(d.EndDay), d.Comment,
+		)
+	case HeartRate:
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO heart_rate (timestamp, bpm, source) VALUES (?, ?, ?)`,
+			sqliteTime(d.Timestamp), d.Bpm, string(d.Source),
+		)
+	case PersonalInfo:
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO personal_info (id, age, height, weight, biological_sex, email)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			d.ID, d.Age, d.Height, d.Weight, d.Sex, d.Email,
+		)
+	case RestMode:
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO rest_mode_period (id, start_day, end_day, start_time, end_time)
+			VALUES (?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.StartDay), sqliteDay(d.EndDay), sqliteTime(d.StartTime), sqliteTime(d.EndTime),
+		)
+		if err == nil {
+			err = replaceSQLiteEpisodes(ctx, tx, d)
+		}
+	case RingConfiguration:
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO ring_configuration (id, color, design, firmware_version, hardware_type, set_up_at,
+				size)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, d.Color, d.Design, d.FirmwareVersion, d.HardwareType, sqliteTimePtr(d.SetUpAt), d.Size,
+		)
+	case Session:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO session (id, day, start_datetime, end_datetime, type, mood)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), sqliteTime(d.StartDatetime), sqliteTime(d.EndDatetime), string(d.Type), string(d.Mood),
+		)
+		if err == nil {
+			err = replaceSQLiteSamples(ctx, tx, "session_sample", "session_id", d.ID, map[string]IntervalItems{
+				"heart_rate":             IntervalItems(d.HeartRateData),
+				"heart_rate_variability": IntervalItems(d.HeartRateVariabilityData),
+				"motion_count":           IntervalItems(d.MotionCountData),
+			})
+		}
+	case Sleep:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO sleep (id, day, type, period, bedtime_start, bedtime_end, average_breath,
+				average_heart_rate, average_hrv, awake_time, deep_sleep_duration, efficiency, latency,
+				light_sleep_duration, low_battery_alert, lowest_heart_rate, movement_30_sec, readiness_score,
+				readiness_temperature_deviation, readiness_temperature_trend_deviation, readiness_score_delta,
+				rem_sleep_duration, restless_periods, sleep_phase_5_min, sleep_score_delta, sleep_algorithm_version,
+				time_in_bed, total_sleep_duration)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), string(d.Type), d.Period, sqliteTime(d.BedtimeStart), sqliteTime(d.BedtimeEnd),
+			d.AverageBreath, d.AverageHeartRate, d.AverageHrv, d.AwakeTime, d.DeepSleepDuration, d.Efficiency,
+			d.Latency, d.LightSleepDuration, d.LowBatteryAlert, d.LowestHeartRate, d.Movement30Sec,
+			d.Readiness.Score, d.Readiness.TemperatureDeviation, d.Readiness.TemperatureTrendDeviation,
+			d.ReadinessScoreDelta, d.RemSleepDuration, d.RestlessPeriods, d.SleepPhase5Min, d.SleepScoreDelta,
+			d.SleepAlgorithmVersion, d.TimeInBed, d.TotalSleepDuration,
+		)
+		if err == nil {
+			err = replaceSQLiteContributors(ctx, tx, "sleep_readiness_contributor", "sleep_id", d.ID,
+				contributorValues(d.Readiness.Contributors))
+		}
+		if err == nil {
+			err = replaceSQLiteSamples(ctx, tx, "sleep_sample", "sleep_id", d.ID, map[string]IntervalItems{
+				"heart_rate": d.HeartRate,
+				"hrv":        d.Hrv,
+			})
+		}
+	case SleepTime:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.ID)
+		}
+		var dayTz, startOffset, endOffset any
+		if d.OptimalBedtime != nil {
+			dayTz, startOffset, endOffset = d.OptimalBedtime.DayTz, d.OptimalBedtime.StartOffset, d.OptimalBedtime.EndOffset
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO sleep_time (id, day, optimal_bedtime_day_tz, optimal_bedtime_start_offset,
+				optimal_bedtime_end_offset, recommendation, status)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, sqliteDay(d.Day), dayTz, startOffset, endOffset, string(d.Recommendation), string(d.Status),
+		)
+	case Workout:
+		if d.Day.IsZero() {
+			return sqliteMissingDay(document, d.Id)
+		}
+		err = execSQLite(ctx, tx,
+			`INSERT OR REPLACE INTO workout (id, day, activity, calories, distance, start_datetime, end_datetime,
+				intensity, label, source)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.Id, sqliteDay(d.Day), string(d.Activity), d.Calories, d.Distance, sqliteTime(d.StartDatetime),
+			sqliteTime(d.EndDatetime), string(d.Intensity), d.Label, string(d.Source),
+		)
+	default:
+		return fmt.Errorf("unsupported document type %T", document)
+	}
+
+	return err
+}
+
+func execSQLite(ctx context.Context, tx *sql.Tx, query string, args ...any) error {
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to export to sqlite with error: %v", err)
+	}
+	return nil
+}
+
+func contributorValues(c Contributors) map[string]int {
+	return map[string]int{
+		"activity_balance":      c.ActivityBalance,
+		"body_temperature":      c.BodyTemperature,
+		"hrv_balance":           c.HrvBalance,
+		"previous_day_activity": c.PreviousDayActivity,
+		"previous_night":        c.PreviousNight,
+		"recovery_index":        c.RecoveryIndex,
+		"resting_heart_rate":    c.RestingHeartRate,
+		"sleep_balance":         c.SleepBalance,
+	}
+}
+
+func replaceSQLiteContributors(ctx context.Context, tx *sql.Tx, table string, parentColumn string, parentId string, values map[string]int) error {
+	err := execSQLite(ctx, tx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, table, parentColumn), parentId)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (%s, name, value) VALUES (?, ?, ?)`, table, parentColumn)
+	for name, value := range values {
+		if err := execSQLite(ctx, tx, insert, parentId, name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceSQLiteSamples(ctx context.Context, tx *sql.Tx, table string, parentColumn string, parentId string, series map[string]IntervalItems) error {
+	err := execSQLite(ctx, tx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, table, parentColumn), parentId)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (%s, series, sample_index, timestamp, value) VALUES (?, ?, ?, ?, ?)`,
+		table, parentColumn)
+	for name, items := range series {
+		for i, sample := range items.Samples() {
+			var value any
+			if sample.Valid {
+				value = sample.Value
+			}
+			if err := execSQLite(ctx, tx, insert, parentId, name, i, sqliteTime(sample.Time), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func replaceSQLiteEpisodes(ctx context.Context, tx *sql.Tx, restMode RestMode) error {
+	if err := execSQLite(ctx, tx, `DELETE FROM rest_mode_episode WHERE rest_mode_period_id = ?`, restMode.ID); err != nil {
+		return err
+	}
+	if err := execSQLite(ctx, tx, `DELETE FROM rest_mode_episode_tag WHERE rest_mode_period_id = ?`, restMode.ID); err != nil {
+		return err
+	}
+
+	for i, episode := range restMode.Episodes {
+		err := execSQLite(ctx, tx,
+			`INSERT INTO rest_mode_episode (rest_mode_period_id, episode_index, timestamp) VALUES (?, ?, ?)`,
+			restMode.ID, i, sqliteTime(episode.Timestamp),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range episode.Tags {
+			err := execSQLite(ctx, tx,
+				`INSERT OR IGNORE INTO rest_mode_episode_tag (rest_mode_period_id, episode_index, tag) VALUES (?, ?, ?)`,
+				restMode.ID, i, tag,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sqliteTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func sqliteTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
+
+// sqliteMissingDay returns the error for a document without the day its table requires.
+func sqliteMissingDay(document any, id string) error {
+	return fmt.Errorf("failed to export %T %s to sqlite with error: missing day", document, id)
+}
+
+// sqliteDay returns d for a column which may be null, required day columns are checked before exporting.
+func sqliteDay(d Date) any {
+	if d.IsZero() {
+		return nil
+	}
+	return d.Format("2006-01-02")
+}
+
+func sqliteDayPtr(d *Date) any {
+	if d == nil {
+		return nil
+	}
+	return sqliteDay(*d)
+}
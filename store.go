@@ -0,0 +1,215 @@
+// This file contains the Store abstraction used to persist Oura Ring documents locally for offline analysis.
+//
+// Documents are kept as StoreRecords keyed by Resource, ID and day.  Writing a record with the same resource and
+// ID as an existing one replaces it.  Heart rates have no ID, so theirs is the UTC timestamp and source, and as the
+// API returns them in UTC their day is the UTC day rather than the user's; query them by timestamp.
+
+package go_oura
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Store persists Oura Ring documents.  Upsert accepts the go_oura document types directly, for example Sleep or
+// HeartRate, as well as their list types such as Sleeps or HeartRates.
+type Store interface {
+	Upsert(ctx context.Context, documents ...any) error
+	Query(ctx context.Context, query StoreQuery) ([]StoreRecord, error)
+}
+
+// StoreRecord is a single document held by a Store along with the keys it is indexed by.
+type StoreRecord struct {
+	Resource Resource `json:"resource"`
+	// ID is the document ID.  HeartRate has no ID so the timestamp, in UTC, is used instead.
+	ID string `json:"id"`
+	// Day is the day the document belongs to as YYYY-MM-DD, or empty for documents without a day such as
+	// PersonalInfo.
+	Day string `json:"day"`
+	// Timestamp is the time the document starts at, when the document has one.
+	Timestamp time.Time       `json:"timestamp"`
+	Document  json.RawMessage `json:"document"`
+}
+
+// Decode unmarshals the stored document into v, which should be a pointer to the go_oura type of the record.
+func (r StoreRecord) Decode(v any) error {
+	if err := json.Unmarshal(r.Document, v); err != nil {
+		return fmt.Errorf("failed to process stored %s document %s with error: %v", r.Resource, r.ID, err)
+	}
+	return nil
+}
+
+// StoreQuery selects records of a single Resource.  Zero values leave a bound open.  StartDay and EndDay are
+// inclusive and compared against the record day, Start and End are compared against the record timestamp with
+// End exclusive.
+type StoreQuery struct {
+	Resource Resource
+	StartDay time.Time
+	EndDay   time.Time
+	Start    time.Time
+	End      time.Time
+}
+
+// Matches reports whether record falls within the query.
+func (q StoreQuery) Matches(record StoreRecord) bool {
+	if q.Resource != "" && record.Resource != q.Resource {
+		return false
+	}
+	if !q.StartDay.IsZero() && (record.Day == "" || record.Day < q.StartDay.Format("2006-01-02")) {
+		return false
+	}
+	if !q.EndDay.IsZero() && (record.Day == "" || record.Day > q.EndDay.Format("2006-01-02")) {
+		return false
+	}
+	if !q.Start.IsZero() && (record.Timestamp.IsZero() || record.Timestamp.Before(q.Start)) {
+		return false
+	}
+	if !q.End.IsZero() && (record.Timestamp.IsZero() || !record.Timestamp.Before(q.End)) {
+		return false
+	}
+	return true
+}
+
+// QueryDocuments runs query against store and decodes each record into T, for example
+//
+//	sleeps, err := QueryDocuments[Sleep](ctx, store, StoreQuery{Resource: ResourceSleep})
+func QueryDocuments[T any](ctx context.Context, store Store, query StoreQuery) ([]T, error) {
+	records, err := store.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]T, len(records))
+	for i, record := range records {
+		if err := record.Decode(&documents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return documents, nil
+}
+
+// NewStoreRecords converts go_oura documents into StoreRecords.  List types such as Sleeps are expanded into one
+// record per item.
+func NewStoreRecords(documents ...any) ([]StoreRecord, error) {
+	var records []StoreRecord
+	for _, document := range documents {
+		for _, item := range expandDocuments(document) {
+			record, err := newStoreRecord(item)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func expandDocuments(document any) []any {
+	switch d := document.(type) {
+	case DailyActivities:
+		return anySlice(d.Items)
+	case DailyReadinesses:
+		return anySlice(d.Items)
+	case DailySleeps:
+		return anySlice(d.Items)
+	case DailySpo2Readings:
+		return anySlice(d.Items)
+	case DailyStresses:
+		return anySlice(d.Items)
+	case EnhancedTags:
+		return anySlice(d.Items)
+	case HeartRates:
+		return anySlice(d.Items)
+	case RestModes:
+		return anySlice(d.Items)
+	case RingConfigurations:
+		return anySlice(d.Items)
+	case Sessions:
+		return anySlice(d.Items)
+	case Sleeps:
+		return anySlice(d.Items)
+	case SleepTimes:
+		return anySlice(d.Items)
+	case Workouts:
+		return anySlice(d.Items)
+	default:
+		return []any{document}
+	}
+}
+
+func newStoreRecord(document any) (StoreRecord, error) {
+	var record StoreRecord
+
+	switch d := document.(type) {
+	case DailyActivity:
+		record = StoreRecord{Resource: ResourceDailyActivity, ID: d.ID, Day: storeDay(d.Day), Timestamp: d.Timestamp}
+	case DailyReadiness:
+		record = StoreRecord{Resource: ResourceDailyReadiness, ID: d.Id, Day: storeDay(d.Day), Timestamp: d.Timestamp}
+	case DailySleep:
+		record = StoreRecord{Resource: ResourceDailySleep, ID: d.ID, Day: storeDay(d.Day), Timestamp: d.Timestamp}
+	case DailySpo2Reading:
+		record = StoreRecord{Resource: ResourceDailySpo2, ID: d.ID, Day: storeDay(d.Day)}
+	case DailyStress:
+		record = StoreRecord{Resource: ResourceDailyStress, ID: d.ID, Day: storeDay(d.Day)}
+	case EnhancedTag:
+		record = StoreRecord{Resource: ResourceEnhancedTag, ID: d.ID}
+		if d.StartDay != nil {
+			record.Day = storeDay(*d.StartDay)
+		}
+		if d.StartTime != nil {
+			record.Timestamp = *d.StartTime
+			if record.Day == "" {
+				record.Day = d.StartTime.Format("2006-01-02")
+			}
+		}
+	case HeartRate:
+		record = StoreRecord{
+			Resource:  ResourceHeartRate,
+			ID:        d.Timestamp.UTC().Format(time.RFC3339Nano) + "/" + string(d.Source),
+			Day:       d.Timestamp.UTC().Format("2006-01-02"),
+			Timestamp: d.Timestamp,
+		}
+	case PersonalInfo:
+		record = StoreRecord{Resource: ResourcePersonalInfo, ID: d.ID}
+	case RestMode:
+		record = StoreRecord{Resource: ResourceRestMode, ID: d.ID, Day: storeDay(d.StartDay), Timestamp: d.StartTime}
+	case RingConfiguration:
+		record = StoreRecord{Resource: ResourceRingConfiguration, ID: d.ID}
+		if d.SetUpAt != nil {
+			record.Day = d.SetUpAt.Format("2006-01-02")
+			record.Timestamp = *d.SetUpAt
+		}
+	case Session:
+		record = StoreRecord{Resource: ResourceSession, ID: d.ID, Day: storeDay(d.Day), Timestamp: d.StartDatetime}
+	case Sleep:
+		record = StoreRecord{Resource: ResourceSleep, ID: d.ID, Day: storeDay(d.Day), Timestamp: d.BedtimeStart}
+	case SleepTime:
+		record = StoreRecord{Resource: ResourceSleepTime, ID: d.ID, Day: storeDay(d.Day)}
+	case Workout:
+		record = StoreRecord{Resource: ResourceWorkout, ID: d.Id, Day: storeDay(d.Day), Timestamp: d.StartDatetime}
+	default:
+		return StoreRecord{}, fmt.Errorf("unsupported document type %T", document)
+	}
+
+	if record.ID == "" {
+		return StoreRecord{}, fmt.Errorf("%s document has no id", record.Resource)
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return StoreRecord{}, fmt.Errorf("failed to encode %s document %s with error: %v", record.Resource, record.ID, err)
+	}
+	record.Document = data
+
+	return record, nil
+}
+
+func storeDay(d Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format("2006-01-02")
+}
@@ -0,0 +1,400 @@
+// This file contains JSONLStore, the default file based Store.
+//
+// Records are appended to JSON lines files partitioned by resource and month, for example
+// <dir>/sleep/2024-01.jsonl.  Records without a day are written to <dir>/<resource>/undated.jsonl.  Appending keeps
+// writes cheap; queries keep only the last record written for each ID, and Compact rewrites the partitions
+// without the replaced records.
+//
+// A document whose day moves to another month, for example a re-scored tag, is removed from its old partition when
+// upserted.  The partition holding each ID is kept in an index built from the files the first time a resource is
+// used, so a directory should only be written by one JSONLStore at a time.
+
+package go_oura
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const undatedPartition = "undated"
+
+// JSONLStore is a Store writing partitioned JSON lines files below a directory.
+type JSONLStore struct {
+	dir string
+	mu  sync.Mutex
+
+	// index holds the partition of each ID by resource, loaded on first use of the resource.
+	index map[Resource]map[string]string
+}
+
+// NewJSONLStore returns a JSONLStore writing below dir, which is created if needed.
+func NewJSONLStore(dir string) (*JSONLStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory with error: %v", err)
+	}
+	return &JSONLStore{dir: dir}, nil
+}
+
+// Upsert appends documents to their partitions.  A document with the same resource and ID as one already stored
+// replaces it.
+func (s *JSONLStore) Upsert(ctx context.Context, documents ...any) error {
+	records, err := NewStoreRecords(documents...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Only the last record for each ID is written, so a document moving partitions within one call is not left
+	// behind in the first.
+	type key struct {
+		resource Resource
+		id       string
+	}
+	last := make(map[key]int)
+	for i, record := range records {
+		last[key{record.Resource, record.ID}] = i
+	}
+
+	partitions := make(map[string][]StoreRecord)
+	stale := make(map[string]map[string]bool)
+	for i, record := range records {
+		if last[key{record.Resource, record.ID}] != i {
+			continue
+		}
+
+		index, err := s.resourceIndex(record.Resource)
+		if err != nil {
+			return err
+		}
+
+		partition := partitionName(record.Day)
+		if previous, ok := index[record.ID]; ok && previous != partition {
+			file := s.partitionPath(record.Resource, previous)
+			if stale[file] == nil {
+				stale[file] = make(map[string]bool)
+			}
+			stale[file][record.ID] = true
+		}
+
+		file := s.partitionPath(record.Resource, partition)
+		partitions[file] = append(partitions[file], record)
+	}
+
+	for file, partition := range partitions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := appendRecords(file, partition); err != nil {
+			return err
+		}
+
+		// The index only moves once the partition holds the records, so a failed write leaves it pointing at the
+		// previous copy.
+		for _, record := range partition {
+			s.index[record.Resource][record.ID] = partitionName(record.Day)
+		}
+	}
+
+	for file, ids := range stale {
+		if err := removeRecords(file, ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteDocuments upserts documents, allowing a JSONLStore to be used as a SyncSink.
+func (s *JSONLStore) WriteDocuments(ctx context.Context, _ Resource, documents []any) error {
+	return s.Upsert(ctx, documents...)
+}
+
+// Query returns the records matching query ordered by day, timestamp and ID.  Query.Resource is required.
+func (s *JSONLStore) Query(ctx context.Context, query StoreQuery) ([]StoreRecord, error) {
+	if query.Resource == "" {
+		return nil, fmt.Errorf("store query requires a resource")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	partitions, err := s.partitions(query.Resource)
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.resourceIndex(query.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []StoreRecord
+	for _, partition := range partitions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !partitionOverlaps(partition, query) {
+			continue
+		}
+
+		partitionRecords, err := readRecords(s.partitionPath(query.Resource, partition))
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range partitionRecords {
+			if index[record.ID] == partition && query.Matches(record) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Day != records[j].Day {
+			return records[i].Day < records[j].Day
+		}
+		if !records[i].Timestamp.Equal(records[j].Timestamp) {
+			return records[i].Timestamp.Before(records[j].Timestamp)
+		}
+		return records[i].ID < records[j].ID
+	})
+
+	return records, nil
+}
+
+// Compact rewrites every partition keeping only the last record written for each ID, and only in the partition the
+// ID was last written to.
+func (s *JSONLStore) Compact(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, resource := range Resources() {
+		partitions, err := s.partitions(resource)
+		if err != nil {
+			return err
+		}
+		index, err := s.resourceIndex(resource)
+		if err != nil {
+			return err
+		}
+
+		for _, partition := range partitions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			file := s.partitionPath(resource, partition)
+			records, err := readRecords(file)
+			if err != nil {
+				return err
+			}
+
+			kept := records[:0]
+			for _, record := range records {
+				if index[record.ID] == partition {
+					kept = append(kept, record)
+				}
+			}
+			if err := writeRecords(file, kept); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceIndex returns the partition of each ID stored for resource, reading the partitions the first time.  When
+// an ID is found in more than one partition, as stores written before documents were moved between partitions may
+// hold, the last partition in name order wins.
+func (s *JSONLStore) resourceIndex(resource Resource) (map[string]string, error) {
+	if index, ok := s.index[resource]; ok {
+		return index, nil
+	}
+
+	partitions, err := s.partitions(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]string)
+	for _, partition := range partitions {
+		records, err := readRecords(s.partitionPath(resource, partition))
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			index[record.ID] = partition
+		}
+	}
+
+	if s.index == nil {
+		s.index = make(map[Resource]map[string]string)
+	}
+	s.index[resource] = index
+	return index, nil
+}
+
+func (s *JSONLStore) partitionPath(resource Resource, partition string) string {
+	return filepath.Join(s.dir, string(resource), partition+".jsonl")
+}
+
+func (s *JSONLStore) partitions(resource Resource) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, string(resource)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list %s partitions with error: %v", resource, err)
+	}
+
+	var partitions []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".jsonl") {
+			partitions = append(partitions, strings.TrimSuffix(entry.Name(), ".jsonl"))
+		}
+	}
+	sort.Strings(partitions)
+
+	return partitions, nil
+}
+
+func partitionName(day string) string {
+	if len(day) < 7 {
+		return undatedPartition
+	}
+	return day[:7]
+}
+
+// partitionOverlaps reports whether a month partition can hold records within the query days.  Timestamp bounds
+// are not used to skip partitions since a record's timestamp may fall in a different month than its day.
+func partitionOverlaps(partition string, query StoreQuery) bool {
+	if partition == undatedPartition {
+		return query.StartDay.IsZero() && query.EndDay.IsZero()
+	}
+	if !query.StartDay.IsZero() && partition < query.StartDay.Format("2006-01") {
+		return false
+	}
+	if !query.EndDay.IsZero() && partition > query.EndDay.Format("2006-01") {
+		return false
+	}
+	return true
+}
+
+// readRecords reads a partition file keeping the last record for each ID in the order the IDs first appear.
+func readRecords(file string) ([]StoreRecord, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open partition %s with error: %v", file, err)
+	}
+	defer f.Close()
+
+	var records []StoreRecord
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var record StoreRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("failed to process partition %s with error: %v", file, err)
+		}
+
+		if i, ok := index[record.ID]; ok {
+			records[i] = record
+		} else {
+			index[record.ID] = len(records)
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read partition %s with error: %v", file, err)
+	}
+
+	return records, nil
+}
+
+func appendRecords(file string, records []StoreRecord) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create partition directory with error: %v", err)
+	}
+
+	data, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open partition %s with error: %v", file, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write partition %s with error: %v", file, err)
+	}
+
+	return f.Close()
+}
+
+// removeRecords rewrites a partition file without the records of ids.
+func removeRecords(file string, ids map[string]bool) error {
+	records, err := readRecords(file)
+	if err != nil {
+		return err
+	}
+
+	kept := records[:0]
+	for _, record := range records {
+		if !ids[record.ID] {
+			kept = append(kept, record)
+		}
+	}
+	return writeRecords(file, kept)
+}
+
+func writeRecords(file string, records []StoreRecord) error {
+	data, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create partition %s with error: %v", file, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write partition %s with error: %v", file, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close partition %s with error: %v", file, err)
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func encodeRecords(records []StoreRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, fmt.Errorf("failed to encode %s record %s with error: %v", record.Resource, record.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
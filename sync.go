@@ -0,0 +1,259 @@
+// This file contains an incremental sync engine which pulls only new or recently changed documents from the API.
+//
+// For every Resource a Checkpoint records the last day which was fully synced along with the window and next token
+// of a sync in progress.  Checkpoints are saved after every page so a sync interrupted mid pagination resumes from
+// the page it stopped at.  Oura re-scores the last few days as more data arrives, so each run re-fetches a
+// look back window ending at the last synced day.
+
+package go_oura
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultSyncLookback is the number of days before the last synced day which are fetched again on each sync.
+const DefaultSyncLookback = 3
+
+// Checkpoint records the sync progress for a single Resource.
+type Checkpoint struct {
+	Resource Resource `json:"resource"`
+	// SyncedThrough is the last day which was fully synced.  Zero when the resource has never completed a sync.
+	SyncedThrough time.Time `json:"synced_through"`
+	// WindowStart and WindowEnd are the days being synced when NextToken is set.
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	// NextToken is the token for the next page of an unfinished sync.
+	NextToken *string   `json:"next_token"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore loads and saves Checkpoints.  LoadCheckpoint returns nil without an error when no checkpoint has
+// been saved for the resource.
+type CheckpointStore interface {
+	LoadCheckpoint(resource Resource) (*Checkpoint, error)
+	SaveCheckpoint(checkpoint Checkpoint) error
+}
+
+// SyncSink receives the documents fetched by a Syncer.  Documents are the go_oura types, for example Sleep or
+// HeartRate.  Pages may be delivered more than once if a sync is interrupted, so sinks should upsert.
+type SyncSink interface {
+	WriteDocuments(ctx context.Context, resource Resource, documents []any) error
+}
+
+// SyncSinkFunc allows an ordinary function to be used as a SyncSink.
+type SyncSinkFunc func(ctx context.Context, resource Resource, documents []any) error
+
+// WriteDocuments calls f(ctx, resource, documents).
+func (f SyncSinkFunc) WriteDocuments(ctx context.Context, resource Resource, documents []any) error {
+	return f(ctx, resource, documents)
+}
+
+// Syncer pulls documents for a set of resources into a SyncSink, tracking progress in a CheckpointStore.
+type Syncer struct {
+	Client      *Client
+	Checkpoints CheckpointStore
+	Sink        SyncSink
+	// Resources to sync.  Defaults to every Resource.
+	Resources []Resource
+	// Start is the first day pulled for a resource which has never been synced.
+	Start time.Time
+	// Lookback is the number of days before the last synced day which are fetched again.
+	Lookback int
+	// Now returns the current time and defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewSyncer returns a Syncer for every Resource starting 30 days ago with the DefaultSyncLookback.
+func NewSyncer(client *Client, checkpoints CheckpointStore, sink SyncSink) *Syncer {
+	return &Syncer{
+		Client:      client,
+		Checkpoints: checkpoints,
+		Sink:        sink,
+		Resources:   Resources(),
+		Start:       time.Now().AddDate(0, 0, -30),
+		Lookback:    DefaultSyncLookback,
+		Now:         time.Now,
+	}
+}
+
+// Run syncs every configured resource.  A failure for one resource does not stop the others; all failures are
+// returned together.
+func (s *Syncer) Run(ctx context.Context) error {
+	var errs []error
+	for _, resource := range s.Resources {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		if err := s.SyncResource(ctx, resource); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SyncResource syncs a single resource, resuming an unfinished sync when the checkpoint holds a next token.
+func (s *Syncer) SyncResource(ctx context.Context, resource Resource) error {
+	fetch, ok := resourceFetchers[resource]
+	if !ok {
+		return fmt.Errorf("unknown resource %s", resource)
+	}
+
+	checkpoint, err := s.Checkpoints.LoadCheckpoint(resource)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint for %s with error: %v", resource, err)
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{Resource: resource}
+	}
+
+	if checkpoint.NextToken == nil {
+		checkpoint.WindowStart, checkpoint.WindowEnd = s.window(*checkpoint)
+	}
+
+	// Requests are made with ctx so cancelling it also stops a request in flight.
+	client := s.Client.withContext(ctx)
+	for {
+		documents, nextToken, err := fetch(client, checkpoint.WindowStart, checkpoint.WindowEnd, checkpoint.NextToken)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s with error: %v", resource, err)
+		}
+
+		if len(documents) > 0 {
+			if err := s.Sink.WriteDocuments(ctx, resource, documents); err != nil {
+				return fmt.Errorf("failed to write %s with error: %v", resource, err)
+			}
+		}
+
+		checkpoint.NextToken = nextToken
+		if nextToken == nil {
+			checkpoint.SyncedThrough = checkpoint.WindowEnd
+		}
+		checkpoint.UpdatedAt = s.now()
+		if err := s.Checkpoints.SaveCheckpoint(*checkpoint); err != nil {
+			return fmt.Errorf("failed to save checkpoint for %s with error: %v", resource, err)
+		}
+
+		if nextToken == nil {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Syncer) window(checkpoint Checkpoint) (time.Time, time.Time) {
+	end := truncateDay(s.now())
+
+	start := truncateDay(s.Start)
+	if !checkpoint.SyncedThrough.IsZero() {
+		start = truncateDay(checkpoint.SyncedThrough).AddDate(0, 0, -s.Lookback)
+	}
+	if start.After(end) {
+		start = end
+	}
+
+	return start, end
+}
+
+func (s *Syncer) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// FileCheckpointStore is a CheckpointStore keeping every checkpoint in a single JSON file.
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore using the JSON file at path.  The file is created on the
+// first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// LoadCheckpoint returns the saved checkpoint for resource, or nil if there is none.
+func (f *FileCheckpointStore) LoadCheckpoint(resource Resource) (*Checkpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, ok := checkpoints[resource]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint stores checkpoint, replacing any previous checkpoint for the same resource.  The file is replaced
+// atomically so a crash never leaves it half written.
+func (f *FileCheckpointStore) SaveCheckpoint(checkpoint Checkpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[checkpoint.Resource] = checkpoint
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints with error: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file with error: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint file with error: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close checkpoint file with error: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *FileCheckpointStore) read() (map[Resource]Checkpoint, error) {
+	checkpoints := make(map[Resource]Checkpoint)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file with error: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to process checkpoint file with error: %v", err)
+	}
+
+	return checkpoints, nil
+}
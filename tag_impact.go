@@ -0,0 +1,265 @@
+// This file contains the analysis of the impact of enhanced tags, such as alcohol or a late meal, on the following
+// night and day.
+//
+// For each tag type the biomarkers of tagged nights are compared with those of untagged nights, giving the difference
+// in means, Cohen's d, and a Welch t confidence interval for the difference, which allows for the groups having
+// different variances and for the few days a tag is usually logged on.  A tag with only days
+// affects the night after each day from StartDay through EndDay.  A tag with a StartTime affects the nights starting
+// from StartTime up to and including the first night after the tag ends, provided that night starts within
+// tagNightWindow of the end, so a gap in the data does not attribute the tag to a later night.
+
+package go_oura
+
+import (
+	"math"
+	"time"
+)
+
+// tagImpactConfidence is the level of the confidence interval.
+const tagImpactConfidence = 0.95
+
+// tagNightWindow is how long after a tag with a StartTime ends a night may start and still be affected by it.
+const tagNightWindow = 24 * time.Hour
+
+// BiomarkerDeepSleep is Sleep.DeepSleepDuration of the main sleep in minutes.  It is only analysed for tag impact,
+// baselines do not track it.
+const BiomarkerDeepSleep Biomarker = "deep_sleep"
+
+// tagImpactBiomarkers returns the biomarkers analysed for tag impact, every Biomarker and BiomarkerDeepSleep.
+func tagImpactBiomarkers() []Biomarker {
+	return append(Biomarkers(), BiomarkerDeepSleep)
+}
+
+// TagEffect is the effect of a tag type on a biomarker.  Difference is TaggedMean - UntaggedMean, with Lower and
+// Upper bounding its 95% confidence interval.  Ok is false, and the statistics are zero, unless both groups have at
+// least two days.
+type TagEffect struct {
+	TagTypeCode  string
+	Biomarker    Biomarker
+	TaggedDays   int
+	UntaggedDays int
+	TaggedMean   float64
+	UntaggedMean float64
+	Difference   float64
+	CohensD      float64
+	Lower        float64
+	Upper        float64
+	Ok           bool
+}
+
+// AnalyzeTagImpact returns the TagEffect of each tag type in tags on each Biomarker of nights and on
+// BiomarkerDeepSleep, ordered by tag type and then Biomarkers order.  Biomarkers without values in nights are left
+// out.
+func AnalyzeTagImpact(tags []EnhancedTag, nights []Night) []TagEffect {
+	sorted := sortNights(nights)
+	values := BiomarkerValues(sorted)
+	for _, night := range sorted {
+		if night.MainSleep != nil && night.MainSleep.DeepSleepDuration > 0 {
+			values[BiomarkerDeepSleep] = append(values[BiomarkerDeepSleep],
+				DailyValue{Day: night.Day, Value: float64(night.MainSleep.DeepSleepDuration) / 60})
+		}
+	}
+
+	tagged := make(map[string]map[string]bool)
+	for _, tag := range tags {
+		if tagged[tag.TagTypeCode] == nil {
+			tagged[tag.TagTypeCode] = make(map[string]bool)
+		}
+		for _, day := range tagAffectedDays(tag, sorted) {
+			tagged[tag.TagTypeCode][day.Format("2006-01-02")] = true
+		}
+	}
+
+	var effects []TagEffect
+	for _, code := range sortedKeys(tagged) {
+		for _, biomarker := range tagImpactBiomarkers() {
+			if len(values[biomarker]) == 0 {
+				continue
+			}
+
+			var withTag, withoutTag []float64
+			for _, value := range values[biomarker] {
+				if tagged[code][value.Day.Format("2006-01-02")] {
+					withTag = append(withTag, value.Value)
+				} else {
+					withoutTag = append(withoutTag, value.Value)
+				}
+			}
+
+			effect := TagEffect{TagTypeCode: code, Biomarker: biomarker, TaggedDays: len(withTag), UntaggedDays: len(withoutTag)}
+			effect.compare(withTag, withoutTag)
+			effects = append(effects, effect)
+		}
+	}
+
+	return effects
+}
+
+// tagAffectedDays returns the days of the nights affected by tag.
+func tagAffectedDays(tag EnhancedTag, nights []Night) []Date {
+	if tag.StartTime == nil {
+		if tag.StartDay == nil {
+			return nil
+		}
+		last := tag.StartDay.Time
+		if tag.EndDay != nil && tag.EndDay.After(last) {
+			last = tag.EndDay.Time
+		}
+
+		var days []Date
+		for day := tag.StartDay.Time; !day.After(last); day = day.AddDate(0, 0, 1) {
+			days = append(days, Date{Time: day.AddDate(0, 0, 1)})
+		}
+		return days
+	}
+
+	end := *tag.StartTime
+	if tag.EndTime != nil && tag.EndTime.After(end) {
+		end = *tag.EndTime
+	}
+
+	var days []Date
+	for _, night := range nights {
+		if night.MainSleep == nil {
+			continue
+		}
+		start := night.MainSleep.BedtimeStart
+		if start.Before(*tag.StartTime) {
+			continue
+		}
+		if start.After(end.Add(tagNightWindow)) {
+			break
+		}
+		days = append(days, night.Day)
+		if !start.Before(end) {
+			break
+		}
+	}
+	return days
+}
+
+// compare sets the statistics comparing withTag with withoutTag.
+func (e *TagEffect) compare(withTag []float64, withoutTag []float64) {
+	if len(withTag) < 2 || len(withoutTag) < 2 {
+		return
+	}
+
+	taggedMean, taggedVariance := meanVariance(withTag)
+	untaggedMean, untaggedVariance := meanVariance(withoutTag)
+	n1, n2 := float64(len(withTag)), float64(len(withoutTag))
+
+	e.TaggedMean = taggedMean
+	e.UntaggedMean = untaggedMean
+	e.Difference = taggedMean - untaggedMean
+
+	pooled := math.Sqrt(((n1-1)*taggedVariance + (n2-1)*untaggedVariance) / (n1 + n2 - 2))
+	if pooled > 0 {
+		e.CohensD = e.Difference / pooled
+	}
+
+	// Welch's interval, with the Welch-Satterthwaite degrees of freedom.
+	a, b := taggedVariance/n1, untaggedVariance/n2
+	var margin float64
+	if a+b > 0 {
+		df := (a + b) * (a + b) / (a*a/(n1-1) + b*b/(n2-1))
+		margin = studentTQuantile(1-(1-tagImpactConfidence)/2, df) * math.Sqrt(a+b)
+	}
+	e.Lower = e.Difference - margin
+	e.Upper = e.Difference + margin
+	e.Ok = true
+}
+
+// studentTQuantile returns the p quantile, for p above 0.5, of Student's t distribution with df degrees of freedom.
+func studentTQuantile(p float64, df float64) float64 {
+	high := 1.0
+	for studentTCDF(high, df) < p {
+		high *= 2
+	}
+
+	low := 0.0
+	for i := 0; i < 100; i++ {
+		middle := (low + high) / 2
+		if studentTCDF(middle, df) < p {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2
+}
+
+// studentTCDF returns the cumulative probability of t, at least 0, under Student's t distribution with df degrees
+// of freedom.
+func studentTCDF(t float64, df float64) float64 {
+	return 1 - incompleteBeta(df/2, 0.5, df/(df+t*t))/2
+}
+
+// incompleteBeta returns the regularized incomplete beta function I_x(a, b), evaluated with the continued fraction
+// of Numerical Recipes.
+func incompleteBeta(a float64, b float64, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly below the mean, above it use I_x(a, b) = 1 - I_1-x(b, a).
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+	}
+	return front * betaContinuedFraction(a, b, x) / a
+}
+
+func betaContinuedFraction(a float64, b float64, x float64) float64 {
+	const tiny = 1e-300
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	fraction := d
+
+	for m := 1.0; m <= 300; m++ {
+		for _, numerator := range []float64{
+			m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)),
+			-(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			fraction *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return fraction
+}
+
+// meanVariance returns the mean and sample variance of values.
+func meanVariance(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, squares / float64(len(values)-1)
+}
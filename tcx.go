@@ -0,0 +1,187 @@
+// This file contains an exporter writing Workouts as Garmin Training Center (TCX) files.
+//
+// A Workout only carries summary values, so the track of each exported workout is built from HeartRate samples
+// falling within the workout's StartDatetime - EndDatetime window.
+// The TCX schema is at https://www8.garmin.com/xmlschemas/TrainingCenterDatabasev2.xsd
+
+package go_oura
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const tcxNamespace = "http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2"
+
+type tcxDatabase struct {
+	XMLName    xml.Name      `xml:"TrainingCenterDatabase"`
+	Xmlns      string        `xml:"xmlns,attr"`
+	Activities []tcxActivity `xml:"Activities>Activity"`
+}
+
+type tcxActivity struct {
+	Sport string `xml:"Sport,attr"`
+	Id    string `xml:"Id"`
+	Lap   tcxLap `xml:"Lap"`
+	Notes string `xml:"Notes,omitempty"`
+}
+
+type tcxLap struct {
+	StartTime        string          `xml:"StartTime,attr"`
+	TotalTimeSeconds float64         `xml:"TotalTimeSeconds"`
+	DistanceMeters   float64         `xml:"DistanceMeters"`
+	Calories         int             `xml:"Calories"`
+	AverageHeartRate *tcxValue       `xml:"AverageHeartRateBpm,omitempty"`
+	MaximumHeartRate *tcxValue       `xml:"MaximumHeartRateBpm,omitempty"`
+	Intensity        string          `xml:"Intensity"`
+	TriggerMethod    string          `xml:"TriggerMethod"`
+	Trackpoints      []tcxTrackpoint `xml:"Track>Trackpoint,omitempty"`
+}
+
+type tcxTrackpoint struct {
+	Time      string    `xml:"Time"`
+	HeartRate *tcxValue `xml:"HeartRateBpm,omitempty"`
+}
+
+type tcxValue struct {
+	Value int `xml:"Value"`
+}
+
+// workoutSport is the sport of a workout activity, shared by the TCX and FIT exporters so they classify activities
+// alike.
+type workoutSport int
+
+const (
+	workoutSportGeneric workoutSport = iota
+	workoutSportRunning
+	workoutSportCycling
+	workoutSportSwimming
+	workoutSportTraining
+	workoutSportWalking
+	workoutSportRowing
+	workoutSportHiking
+)
+
+// workoutSports maps lower case workout activities to their sport.  Activities not listed are generic.
+var workoutSports = map[string]workoutSport{
+	"running":           workoutSportRunning,
+	"treadmill_running": workoutSportRunning,
+	"trail_running":     workoutSportRunning,
+	"jogging":           workoutSportRunning,
+	"cycling":           workoutSportCycling,
+	"indoor_cycling":    workoutSportCycling,
+	"mountain_biking":   workoutSportCycling,
+	"biking":            workoutSportCycling,
+	"spinning":          workoutSportCycling,
+	"swimming":          workoutSportSwimming,
+	"strength_training": workoutSportTraining,
+	"weight_lifting":    workoutSportTraining,
+	"crossfit":          workoutSportTraining,
+	"hiit":              workoutSportTraining,
+	"walking":           workoutSportWalking,
+	"rowing":            workoutSportRowing,
+	"hiking":            workoutSportHiking,
+}
+
+func sportOf(activity WorkoutActivity) workoutSport {
+	return workoutSports[strings.ToLower(string(activity))]
+}
+
+// TCXSport maps a Workout activity to one of the sports TCX supports: Running, Biking or Other.
+func TCXSport(activity WorkoutActivity) string {
+	switch sportOf(activity) {
+	case workoutSportRunning:
+		return "Running"
+	case workoutSportCycling:
+		return "Biking"
+	default:
+		return "Other"
+	}
+}
+
+// WriteTCX writes workouts as a TCX document with one activity per workout.  Each activity has a single lap whose
+// track holds the heartRates samples within the workout window.  heartRates does not need to be sorted and may
+// cover more than the workouts.
+func WriteTCX(w io.Writer, workouts []Workout, heartRates []HeartRate) error {
+	sorted := make([]HeartRate, len(heartRates))
+	copy(sorted, heartRates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	database := tcxDatabase{Xmlns: tcxNamespace}
+	for _, workout := range workouts {
+		database.Activities = append(database.Activities, tcxWorkoutActivity(workout, sorted))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write TCX with error: %v", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(database); err != nil {
+		return fmt.Errorf("failed to write TCX with error: %v", err)
+	}
+
+	return nil
+}
+
+// WriteWorkoutTCX fetches the heart rates recorded during workout and writes it to w as a TCX document.
+func (c *Client) WriteWorkoutTCX(w io.Writer, workout Workout) error {
+	heartRates, err := c.heartRatesBetween(workout.StartDatetime, workout.EndDatetime)
+	if err != nil {
+		return err
+	}
+
+	return WriteTCX(w, []Workout{workout}, heartRates)
+}
+
+func tcxWorkoutActivity(workout Workout, sorted []HeartRate) tcxActivity {
+	start := workout.StartDatetime.UTC()
+
+	lap := tcxLap{
+		StartTime:        start.Format(time.RFC3339),
+		TotalTimeSeconds: workout.EndDatetime.Sub(workout.StartDatetime).Seconds(),
+		DistanceMeters:   workout.Distance,
+		Calories:         int(math.Round(workout.Calories)),
+		Intensity:        "Active",
+		TriggerMethod:    "Manual",
+	}
+
+	first := sort.Search(len(sorted), func(i int) bool { return !sorted[i].Timestamp.Before(workout.StartDatetime) })
+	total, maximum := 0, 0
+	for _, heartRate := range sorted[first:] {
+		if heartRate.Timestamp.After(workout.EndDatetime) {
+			break
+		}
+
+		lap.Trackpoints = append(lap.Trackpoints, tcxTrackpoint{
+			Time:      heartRate.Timestamp.UTC().Format(time.RFC3339),
+			HeartRate: &tcxValue{Value: heartRate.Bpm},
+		})
+		total += heartRate.Bpm
+		if heartRate.Bpm > maximum {
+			maximum = heartRate.Bpm
+		}
+	}
+	if len(lap.Trackpoints) > 0 {
+		lap.AverageHeartRate = &tcxValue{Value: int(math.Round(float64(total) / float64(len(lap.Trackpoints))))}
+		lap.MaximumHeartRate = &tcxValue{Value: maximum}
+	}
+
+	notes := string(workout.Activity)
+	if workout.Label != "" {
+		notes = workout.Label + " (" + string(workout.Activity) + ")"
+	}
+
+	return tcxActivity{
+		Sport: TCXSport(workout.Activity),
+		Id:    start.Format(time.RFC3339),
+		Lap:   lap,
+		Notes: notes,
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeActivityClasses(t *testing.T) {
+	start := time.Date(2024, 1, 1, 4, 0, 0, 0, time.FixedZone("", -5*60*60))
+
+	classes, err := go_oura.DecodeActivityClasses("0112235", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	minute := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+	expected := go_oura.ActivityClasses{
+		{Start: minute(0), End: minute(5), Class: go_oura.ActivityClassNonWear},
+		{Start: minute(5), End: minute(15), Class: go_oura.ActivityClassRest},
+		{Start: minute(15), End: minute(25), Class: go_oura.ActivityClassInactive},
+		{Start: minute(25), End: minute(30), Class: go_oura.ActivityClassLow},
+		{Start: minute(30), End: minute(35), Class: go_oura.ActivityClassHigh},
+	}
+	if !reflect.DeepEqual(classes, expected) {
+		t.Errorf("Expected %v, got %v", expected, classes)
+	}
+
+	if _, err := go_oura.DecodeActivityClasses("16", start); err == nil {
+		t.Errorf("Expected error for an invalid activity class")
+	}
+}
+
+func TestActivityClasses_SedentaryBouts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC)
+	classes, err := go_oura.DecodeActivityClasses("2222223222222222222221222", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	bouts := classes.SedentaryBouts(time.Hour)
+	if len(bouts) != 1 || !bouts[0].Start.Equal(start.Add(35*time.Minute)) || bouts[0].Duration() != 70*time.Minute {
+		t.Errorf("Expected a single 70 minute bout, got %v", bouts)
+	}
+
+	if bouts := classes.SedentaryBouts(0); len(bouts) != 3 {
+		t.Errorf("Expected 3 bouts without a minimum, got %v", bouts)
+	}
+}
+
+func TestDailyActivity_ActivityClasses(t *testing.T) {
+	activity := go_oura.DailyActivity{
+		Class5Min:          "111122321111111111111111111111111111233211111111111111113433322233232322223332230343333333333222222222222233222211111112323332200332333222323333323222322332232222222223222222222221123112111122222233232222222222221333332211111111111111111111111111111111111111123211111111111111111111111111",
+		HighActivityTime:   120,
+		LowActivityTime:    13620,
+		MediumActivityTime: 1020,
+		NonWearTime:        1200,
+		RestingTime:        40200,
+		SedentaryTime:      30240,
+		Timestamp:          time.Date(2024, 1, 1, 4, 0, 0, 0, time.FixedZone("", -5*60*60)),
+	}
+
+	classes, err := activity.ActivityClasses()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !classes[0].Start.Equal(activity.Timestamp) || !classes[len(classes)-1].End.Equal(activity.Timestamp.Add(24*time.Hour)) {
+		t.Errorf("Expected classes to cover the activity day, got %v - %v", classes[0].Start, classes[len(classes)-1].End)
+	}
+
+	totals := classes.Totals()
+	if totals[go_oura.ActivityClassRest] != 38700*time.Second || totals[go_oura.ActivityClassHigh] != 0 {
+		t.Errorf("Unexpected totals %v", totals)
+	}
+
+	mismatches := classes.CheckDurations(activity, 25*time.Minute)
+	if len(mismatches) != 2 || mismatches[0].Class != go_oura.ActivityClassInactive || mismatches[1].Class != go_oura.ActivityClassLow {
+		t.Errorf("Expected inactive and low mismatches, got %v", mismatches)
+	}
+}
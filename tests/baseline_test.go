@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"math"
+	"testing"
+	"time"
+)
+
+func baselineDay(d int) go_oura.Date {
+	return go_oura.Date{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestBaselineCalculator_Deviations(t *testing.T) {
+	// Values are given newest first.
+	var values []go_oura.DailyValue
+	daily := []float64{50, 52, 48, 50, 51, 49, 50, 52, 48, 60}
+	for i := len(daily) - 1; i >= 0; i-- {
+		values = append(values, go_oura.DailyValue{Day: baselineDay(i + 1), Value: daily[i]})
+	}
+
+	deviations := (&go_oura.BaselineCalculator{Window: 14, MinDays: 5}).Deviations(values)
+	if len(deviations) != 10 || !deviations[0].Day.Equal(baselineDay(1).Time) {
+		t.Fatalf("Expected 10 deviations in day order, got %v", deviations)
+	}
+
+	if deviations[4].Ok || deviations[4].Days != 4 {
+		t.Errorf("Expected no baseline with 4 days, got %v", deviations[4])
+	}
+	if !deviations[5].Ok || deviations[5].Median != 50 {
+		t.Errorf("Expected a baseline of 50 with 5 days, got %v", deviations[5])
+	}
+
+	last := deviations[9]
+	if !last.Ok || last.Median != 50 || math.Abs(last.Spread-1.4826) > 1e-9 {
+		t.Errorf("Expected median 50 and spread 1.4826, got %v and %v", last.Median, last.Spread)
+	}
+	if math.Abs(last.ZScore-10/1.4826) > 1e-9 {
+		t.Errorf("Expected %v, got %v", 10/1.4826, last.ZScore)
+	}
+}
+
+func TestBaselineCalculator_DeviationsWindow(t *testing.T) {
+	values := []go_oura.DailyValue{
+		{Day: baselineDay(1), Value: 100},
+		{Day: baselineDay(2), Value: 100},
+		{Day: baselineDay(6), Value: 50},
+		{Day: baselineDay(7), Value: 50},
+		{Day: baselineDay(8), Value: 54},
+		{Day: baselineDay(9), Value: 50},
+		{Day: baselineDay(10), Value: 51},
+	}
+
+	deviations := go_oura.NewBaselineCalculator(4).Deviations(values)
+
+	// Days 6 through 9 are in the window of day 10, the MAD is zero so the mean absolute deviation is used.
+	last := deviations[6]
+	if !last.Ok || last.Days != 4 || last.Median != 50 {
+		t.Fatalf("Expected a baseline of 50 from 4 days, got %v", last)
+	}
+	if math.Abs(last.Spread-1.2533) > 1e-9 || math.Abs(last.ZScore-1/1.2533) > 1e-9 {
+		t.Errorf("Expected spread 1.2533 and z-score %v, got %v and %v", 1/1.2533, last.Spread, last.ZScore)
+	}
+
+	// Only day 6 is in the window of day 7, and a single value does not vary.
+	if deviations[3].Ok || deviations[3].Days != 1 {
+		t.Errorf("Expected no baseline from a single day, got %v", deviations[3])
+	}
+}
+
+func TestBiomarkerValues(t *testing.T) {
+	nights := []go_oura.Night{
+		{
+			Day:            baselineDay(1),
+			MainSleep:      &go_oura.Sleep{LowestHeartRate: 52, AverageHrv: 45, AverageBreath: 14.5},
+			DailyReadiness: &go_oura.DailyReadiness{Score: 80, TemperatureDeviation: 0},
+			DailySleep:     &go_oura.DailySleep{Score: 75},
+		},
+		{
+			Day:       baselineDay(2),
+			MainSleep: &go_oura.Sleep{LowestHeartRate: 54},
+		},
+	}
+
+	// A readiness without a score or temperature deviation, and a sleep without a score, add no values.
+	var readiness go_oura.DailyReadiness
+	document := `{"id":"c","contributors":{"activity_balance":0,"body_temperature":0,"hrv_balance":0,"previous_day_activity":0,"previous_night":0,"recovery_index":0,"resting_heart_rate":0,"sleep_balance":0},"day":"2024-01-03","score":null,"temperature_deviation":null,"temperature_trend_deviation":null,"timestamp":"2024-01-03T00:00:00+00:00"}`
+	if err := json.Unmarshal([]byte(document), &readiness); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	encoded, err := json.Marshal(readiness)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded go_oura.DailyReadiness
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !readiness.TemperatureDeviationMissing || !decoded.TemperatureDeviationMissing {
+		t.Errorf("Expected a missing temperature deviation to survive a round trip, got %s", encoded)
+	}
+	nights = append(nights, go_oura.Night{Day: baselineDay(3), DailyReadiness: &readiness, DailySleep: &go_oura.DailySleep{}})
+
+	values := go_oura.BiomarkerValues(nights)
+	expected := map[go_oura.Biomarker]int{
+		go_oura.BiomarkerRestingHeartRate:     2,
+		go_oura.BiomarkerHrv:                  1,
+		go_oura.BiomarkerBreathingRate:        1,
+		go_oura.BiomarkerTemperatureDeviation: 1,
+		go_oura.BiomarkerReadinessScore:       1,
+		go_oura.BiomarkerSleepScore:           1,
+	}
+	for _, biomarker := range go_oura.Biomarkers() {
+		if len(values[biomarker]) != expected[biomarker] {
+			t.Errorf("Expected %v %s values, got %v", expected[biomarker], biomarker, values[biomarker])
+		}
+	}
+	if values[go_oura.BiomarkerRestingHeartRate][1].Value != 54 {
+		t.Errorf("Expected %v, got %v", 54, values[go_oura.BiomarkerRestingHeartRate][1].Value)
+	}
+
+	deviations := go_oura.NewBaselineCalculator(14).Biomarkers(nights)
+	if len(deviations[go_oura.BiomarkerRestingHeartRate]) != 2 {
+		t.Errorf("Expected 2 resting heart rate deviations, got %v", deviations[go_oura.BiomarkerRestingHeartRate])
+	}
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/austinmoody/go_oura"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVEncoder_EncodeDailyReadinesses(t *testing.T) {
+	readinesses := []go_oura.DailyReadiness{
+		{
+			Id: "1",
+			Contributors: go_oura.ReadinessContributors{
+				ActivityBalance: 74,
+				SleepBalance:    79,
+			},
+			Day:                  go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			Score:                61,
+			TemperatureDeviation: -0.09,
+			Timestamp:            time.Date(2024, 1, 21, 0, 0, 0, 0, time.FixedZone("", -5*60*60)),
+		},
+	}
+
+	tt := []struct {
+		name     string
+		options  go_oura.CSVOptions
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Selected_Columns",
+			options:  go_oura.CSVOptions{Columns: []string{"id", "score", "contributors_sleep_balance"}},
+			expected: "id,score,contributors_sleep_balance\n1,61,79\n",
+		},
+		{
+			name:     "Location",
+			options:  go_oura.CSVOptions{Columns: []string{"day", "timestamp"}, Location: time.UTC},
+			expected: "day,timestamp\n2024-01-21,2024-01-21T05:00:00Z\n",
+		},
+		{
+			name:    "Unknown_Column",
+			options: go_oura.CSVOptions{Columns: []string{"nope"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := go_oura.NewCSVEncoder(&buf, tc.options).EncodeDailyReadinesses(readinesses)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestCSVEncoder_EncodeSleepsHeader(t *testing.T) {
+	var buf bytes.Buffer
+	err := go_oura.NewCSVEncoder(&buf, go_oura.CSVOptions{}).EncodeSleeps([]go_oura.Sleep{{ID: "1"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	header := strings.Split(strings.Split(buf.String(), "\n")[0], ",")
+	for _, expected := range []string{"id", "day", "readiness_score", "readiness_contributors_hrv_balance", "sleep_phase_5_min"} {
+		found := false
+		for _, column := range header {
+			if column == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected column %s in header %v", expected, header)
+		}
+	}
+}
+
+func TestCSVEncoder_EncodeSleepsLong(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, time.UTC)
+	sleeps := []go_oura.Sleep{
+		{
+			ID:        "1",
+			Day:       go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			HeartRate: go_oura.IntervalItems{Interval: 300, Items: []float64{64, 62}, Timestamp: start},
+			Hrv:       go_oura.IntervalItems{Interval: 300, Items: []float64{30}, Timestamp: start},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := go_oura.NewCSVEncoder(&buf, go_oura.CSVOptions{Long: true}).EncodeSleeps(sleeps)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "id,day,series,timestamp,value\n" +
+		"1,2024-01-21,heart_rate,2024-01-21T01:28:28Z,64\n" +
+		"1,2024-01-21,heart_rate,2024-01-21T01:33:28Z,62\n" +
+		"1,2024-01-21,hrv,2024-01-21T01:28:28Z,30\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestCSVEncoder_EncodeSleepsLongGaps(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, time.UTC)
+	sleeps := []go_oura.Sleep{
+		{
+			ID:        "1",
+			Day:       go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			HeartRate: go_oura.IntervalItems{Interval: 300, Items: []float64{64, 0, 0}, Timestamp: start, Gaps: []bool{false, true, false}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := go_oura.NewCSVEncoder(&buf, go_oura.CSVOptions{Long: true}).EncodeSleeps(sleeps)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "id,day,series,timestamp,value\n" +
+		"1,2024-01-21,heart_rate,2024-01-21T01:28:28Z,64\n" +
+		"1,2024-01-21,heart_rate,2024-01-21T01:33:28Z,\n" +
+		"1,2024-01-21,heart_rate,2024-01-21T01:38:28Z,0\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestCSVEncoder_EncodeRestModes(t *testing.T) {
+	restModes := []go_oura.RestMode{
+		{
+			ID: "1",
+			Episodes: []go_oura.Episode{
+				{Tags: []string{"tag_generic_fatigue", "tag_generic_headache"}, Timestamp: time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)},
+				{Tags: []string{"tag_generic_recovered"}, Timestamp: time.Date(2024, 1, 4, 8, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := go_oura.NewCSVEncoder(&buf, go_oura.CSVOptions{Columns: []string{"id", "episode_count", "episodes"}}).EncodeRestModes(restModes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "id,episode_count,episodes\n" +
+		"1,2,2024-01-02T08:00:00Z=tag_generic_fatigue|tag_generic_headache;2024-01-04T08:00:00Z=tag_generic_recovered\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"sync"
+	"testing"
+)
+
+func TestEnum_UnmarshalText(t *testing.T) {
+	var mu sync.Mutex
+	var reported []string
+	go_oura.SetUnknownEnumHandler(func(typeName string, value string) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, typeName+"="+value)
+	})
+	defer go_oura.SetUnknownEnumHandler(nil)
+
+	tests := []struct {
+		name          string
+		json          string
+		expected      go_oura.Workout
+		expectedKnown bool
+		reported      []string
+	}{
+		{
+			name:          "known values",
+			json:          `{"id":"1","activity":"walking","calories":0,"day":"2024-01-06","distance":0,"end_datetime":"2024-01-06T09:27:00-05:00","intensity":"moderate","label":null,"source":"autodetected","start_datetime":"2024-01-06T09:14:00-05:00"}`,
+			expected:      go_oura.Workout{Activity: go_oura.WorkoutActivityWalking, Intensity: go_oura.WorkoutIntensityModerate, Source: go_oura.WorkoutSourceAutodetected},
+			expectedKnown: true,
+		},
+		{
+			name:          "unknown values preserved",
+			json:          `{"id":"1","activity":"underwater_hockey","calories":0,"day":"2024-01-06","distance":0,"end_datetime":"2024-01-06T09:27:00-05:00","intensity":"extreme","label":null,"source":"autodetected","start_datetime":"2024-01-06T09:14:00-05:00"}`,
+			expected:      go_oura.Workout{Activity: "underwater_hockey", Intensity: "extreme", Source: go_oura.WorkoutSourceAutodetected},
+			expectedKnown: false,
+			// WorkoutActivity is open ended and never reported.
+			reported: []string{"WorkoutIntensity=extreme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+
+			var workout go_oura.Workout
+			if err := json.Unmarshal([]byte(tt.json), &workout); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if workout.Activity != tt.expected.Activity || workout.Intensity != tt.expected.Intensity || workout.Source != tt.expected.Source {
+				t.Errorf("Expected %v, got %v", tt.expected, workout)
+			}
+			if workout.Activity.Known() != tt.expectedKnown || workout.Intensity.Known() != tt.expectedKnown {
+				t.Errorf("Expected known to be %v", tt.expectedKnown)
+			}
+			if len(reported) != len(tt.reported) {
+				t.Fatalf("Expected %v reported, got %v", tt.reported, reported)
+			}
+			for i := range reported {
+				if reported[i] != tt.reported[i] {
+					t.Errorf("Expected %v reported, got %v", tt.reported, reported)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownEnums(t *testing.T) {
+	var sessions go_oura.Sessions
+	err := json.Unmarshal([]byte(`{"data":[{"id":"1","day":"2024-01-10","start_datetime":"2024-01-10T08:00:00+00:00","end_datetime":"2024-01-10T08:10:00+00:00","type":"breathing","mood":"good","heart_rate":null,"heart_rate_variability":null,"motion_count":null},{"id":"2","day":"2024-01-10","start_datetime":"2024-01-10T09:00:00+00:00","end_datetime":"2024-01-10T09:10:00+00:00","type":"levitation","mood":"serene","heart_rate":null,"heart_rate_variability":null,"motion_count":null}],"next_token":null}`), &sessions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []go_oura.UnknownEnum{
+		{Field: "Items[1].Type", Type: "SessionType", Value: "levitation"},
+		{Field: "Items[1].Mood", Type: "SessionMood", Value: "serene"},
+	}
+	unknown := go_oura.UnknownEnums(sessions)
+	if len(unknown) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, unknown)
+	}
+	for i := range expected {
+		if unknown[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], unknown[i])
+		}
+	}
+
+	workout := &go_oura.Workout{Activity: "underwater_hockey", Intensity: go_oura.WorkoutIntensityEasy}
+	if unknown := go_oura.UnknownEnums(workout); len(unknown) != 0 {
+		t.Errorf("Expected open ended activities to be left out, got %v", unknown)
+	}
+}
+
+func TestEnum_NullNotReported(t *testing.T) {
+	reported := 0
+	go_oura.SetUnknownEnumHandler(func(string, string) { reported++ })
+	defer go_oura.SetUnknownEnumHandler(nil)
+
+	var stress go_oura.DailyStress
+	err := json.Unmarshal([]byte(`{"id":"1","day":"2024-01-10","stress_high":0,"recovery_high":0,"day_summary":null}`), &stress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if stress.DaySummary != "" || stress.DaySummary.Known() || reported != 0 {
+		t.Errorf("Expected an empty, unreported day summary, got %q and %d reports", stress.DaySummary, reported)
+	}
+}
+
+func TestEnum_MarshalText(t *testing.T) {
+	data, err := json.Marshal(map[go_oura.SleepType]go_oura.SessionMood{go_oura.SleepTypeLongSleep: go_oura.SessionMoodGreat})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"long_sleep":"great"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(data))
+	}
+
+	if go_oura.SleepTypeLateNap.String() != "late_nap" {
+		t.Errorf("Expected late_nap, got %s", go_oura.SleepTypeLateNap.String())
+	}
+}
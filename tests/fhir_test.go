@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFHIRConverter_HeartRateObservations(t *testing.T) {
+	timestamp := time.Date(2024, 1, 10, 1, 45, 45, 0, time.FixedZone("", -5*60*60))
+
+	observations := go_oura.FHIRConverter{Subject: "Patient/123"}.HeartRateObservations([]go_oura.HeartRate{
+		{Bpm: 74, Source: "awake", Timestamp: timestamp},
+	})
+
+	expected := go_oura.FHIRObservation{
+		ResourceType: "Observation",
+		ID:           "heart-rate-1704869145",
+		Status:       "final",
+		Category: []go_oura.FHIRCodeableConcept{
+			{Coding: []go_oura.FHIRCoding{{System: "http://terminology.hl7.org/CodeSystem/observation-category", Code: "vital-signs", Display: "Vital Signs"}}},
+		},
+		Code: go_oura.FHIRCodeableConcept{
+			Coding: []go_oura.FHIRCoding{{System: "http://loinc.org", Code: "8867-4", Display: "Heart rate"}},
+			Text:   "Heart rate",
+		},
+		Subject:           &go_oura.FHIRReference{Reference: "Patient/123"},
+		EffectiveDateTime: "2024-01-10T01:45:45-05:00",
+		ValueQuantity:     &go_oura.FHIRQuantity{Value: 74, Unit: "beats/minute", System: "http://unitsofmeasure.org", Code: "/min"},
+	}
+
+	if len(observations) != 1 || !reflect.DeepEqual(observations[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, observations)
+	}
+}
+
+func TestFHIRConverter_SleepDurationObservations(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, time.UTC)
+	observations := go_oura.FHIRConverter{}.SleepDurationObservations([]go_oura.Sleep{
+		{ID: "1", BedtimeStart: start, BedtimeEnd: start.Add(8 * time.Hour), TotalSleepDuration: 27000},
+	})
+
+	if len(observations) != 1 {
+		t.Fatalf("Expected 1 observation, got %d", len(observations))
+	}
+
+	observation := observations[0]
+	if observation.Subject != nil {
+		t.Errorf("Expected no subject, got %v", observation.Subject)
+	}
+	if observation.ValueQuantity.Value != 7.5 || observation.ValueQuantity.Code != "h" {
+		t.Errorf("Expected 7.5 h, got %v", observation.ValueQuantity)
+	}
+	if observation.EffectivePeriod.Start != "2024-01-21T01:28:28+00:00" || observation.EffectivePeriod.End != "2024-01-21T09:28:28+00:00" {
+		t.Errorf("Unexpected effective period %v", observation.EffectivePeriod)
+	}
+}
+
+func TestWriteFHIRBundle(t *testing.T) {
+	day := go_oura.Date{Time: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)}
+	converter := go_oura.FHIRConverter{}
+
+	var observations []go_oura.FHIRObservation
+	observations = append(observations, converter.Spo2Observations([]go_oura.DailySpo2Reading{{ID: "a", Day: day, Percentage: go_oura.Spo2Percentage{Average: 98.7}}})...)
+	observations = append(observations, converter.StepsObservations([]go_oura.DailyActivity{{ID: "b", Day: day, Steps: 9000}})...)
+	observations = append(observations, converter.TemperatureDeviationObservations([]go_oura.DailyReadiness{{Id: "c", Day: day, TemperatureDeviation: 0.2}})...)
+
+	var buf bytes.Buffer
+	if err := go_oura.WriteFHIRBundle(&buf, go_oura.NewFHIRBundle(observations...)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var bundle map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &bundle); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bundle["resourceType"] != "Bundle" || bundle["type"] != "collection" {
+		t.Errorf("Unexpected bundle header %v", bundle)
+	}
+
+	entries := bundle["entry"].([]any)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	expectedIds := []string{"spo2-a", "steps-b", "temperature-deviation-c"}
+	for i, entry := range entries {
+		resource := entry.(map[string]any)["resource"].(map[string]any)
+		if resource["id"] != expectedIds[i] {
+			t.Errorf("Expected id %s, got %v", expectedIds[i], resource["id"])
+		}
+		period := resource["effectivePeriod"].(map[string]any)
+		if period["start"] != "2024-01-09" {
+			t.Errorf("Expected period starting 2024-01-09, got %v", period)
+		}
+	}
+}
+
+func TestTemperatureDeviationObservations(t *testing.T) {
+	day := go_oura.Date{Time: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)}
+	observations := go_oura.FHIRConverter{}.TemperatureDeviationObservations([]go_oura.DailyReadiness{{Id: "c", Day: day, TemperatureDeviation: 0.3}})
+	if len(observations) != 1 {
+		t.Fatalf("Expected 1 observation, got %d", len(observations))
+	}
+
+	// A deviation must not be coded as an absolute body temperature.
+	coding := observations[0].Code.Coding
+	if len(coding) != 1 || coding[0].Code != "temperature_deviation" {
+		t.Fatalf("Expected only the temperature_deviation code, got %v", coding)
+	}
+	if coding[0].System != "https://github.com/austinmoody/go_oura/fhir/CodeSystem/oura" {
+		t.Errorf("Expected the go_oura code system, got %v", coding[0].System)
+	}
+	if observations[0].ValueQuantity.Value != 0.3 || observations[0].ValueQuantity.Code != "Cel" {
+		t.Errorf("Expected 0.3 Cel, got %v", observations[0].ValueQuantity)
+	}
+
+	// Without a LOINC code the observation does not conform to the vital-signs profile.
+	if len(observations[0].Category) != 0 {
+		t.Errorf("Expected no category, got %v", observations[0].Category)
+	}
+}
@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const fitEpoch = 631065600
+
+type fitMessage struct {
+	global uint16
+	fields map[uint8]uint32
+}
+
+// decodeFIT is a minimal FIT decoder supporting the little endian, normal header messages go_oura writes.  It checks
+// the header and file CRCs.
+func decodeFIT(data []byte) ([]fitMessage, error) {
+	if len(data) < 16 || data[0] != 14 || string(data[8:12]) != ".FIT" {
+		return nil, fmt.Errorf("invalid header")
+	}
+	if binary.LittleEndian.Uint16(data[12:]) != fitTestCRC(data[:12]) {
+		return nil, fmt.Errorf("invalid header crc")
+	}
+	size := int(binary.LittleEndian.Uint32(data[4:]))
+	if len(data) != 14+size+2 {
+		return nil, fmt.Errorf("expected %d bytes, got %d", 14+size+2, len(data))
+	}
+	if binary.LittleEndian.Uint16(data[14+size:]) != fitTestCRC(data[:14+size]) {
+		return nil, fmt.Errorf("invalid file crc")
+	}
+
+	type definition struct {
+		global uint16
+		fields [][2]uint8
+	}
+	definitions := map[uint8]definition{}
+
+	var messages []fitMessage
+	records := data[14 : 14+size]
+	for i := 0; i < len(records); {
+		header := records[i]
+		local := header & 0x0F
+		i++
+
+		if header&0x40 != 0 {
+			d := definition{global: binary.LittleEndian.Uint16(records[i+2:])}
+			count := int(records[i+4])
+			i += 5
+			for f := 0; f < count; f++ {
+				d.fields = append(d.fields, [2]uint8{records[i], records[i+1]})
+				i += 3
+			}
+			definitions[local] = d
+			continue
+		}
+
+		d, ok := definitions[local]
+		if !ok {
+			return nil, fmt.Errorf("undefined local message %d", local)
+		}
+		message := fitMessage{global: d.global, fields: map[uint8]uint32{}}
+		for _, field := range d.fields {
+			var value uint32
+			switch field[1] {
+			case 1:
+				value = uint32(records[i])
+			case 2:
+				value = uint32(binary.LittleEndian.Uint16(records[i:]))
+			case 4:
+				value = binary.LittleEndian.Uint32(records[i:])
+			}
+			message.fields[field[0]] = value
+			i += int(field[1])
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func fitTestCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		for bit := 0; bit < 8; bit++ {
+			if (crc^uint16(b>>bit))&1 != 0 {
+				crc = (crc >> 1) ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+func fitMessages(messages []fitMessage, global uint16) []fitMessage {
+	var found []fitMessage
+	for _, message := range messages {
+		if message.global == global {
+			found = append(found, message)
+		}
+	}
+	return found
+}
+
+func TestWriteWorkoutFIT(t *testing.T) {
+	start := time.Date(2024, 1, 6, 9, 14, 0, 0, time.FixedZone("", -5*60*60))
+	workout := go_oura.Workout{
+		Id:            "1",
+		Activity:      "cycling",
+		Calories:      29.627,
+		Distance:      4689.8,
+		StartDatetime: start,
+		EndDatetime:   start.Add(13 * time.Minute),
+	}
+	heartRates := []go_oura.HeartRate{
+		{Bpm: 140, Source: "workout", Timestamp: start.Add(5 * time.Minute)},
+		{Bpm: 90, Source: "awake", Timestamp: start.Add(-5 * time.Minute)},
+		{Bpm: 120, Source: "workout", Timestamp: start.Add(time.Minute)},
+		{Bpm: 100, Source: "awake", Timestamp: start.Add(20 * time.Minute)},
+	}
+
+	var buf bytes.Buffer
+	if err := go_oura.WriteWorkoutFIT(&buf, workout, heartRates); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages, err := decodeFIT(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fileIds := fitMessages(messages, 0)
+	if len(fileIds) != 1 || fileIds[0].fields[0] != 4 {
+		t.Errorf("Expected an activity file_id, got %v", fileIds)
+	}
+
+	records := fitMessages(messages, 20)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records within the workout, got %d", len(records))
+	}
+	expectedTime := uint32(start.Add(time.Minute).Unix() - fitEpoch)
+	if records[0].fields[253] != expectedTime || records[0].fields[3] != 120 || records[1].fields[3] != 140 {
+		t.Errorf("Expected records in time order, got %v", records)
+	}
+
+	sessions := fitMessages(messages, 18)
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessions))
+	}
+	session := sessions[0].fields
+	expected := map[uint8]uint32{
+		2:  uint32(start.Unix() - fitEpoch),
+		5:  2,
+		7:  780000,
+		9:  468980,
+		11: 30,
+		16: 130,
+		17: 140,
+	}
+	for field, value := range expected {
+		if session[field] != value {
+			t.Errorf("Expected session field %d to be %d, got %d", field, value, session[field])
+		}
+	}
+
+	if len(fitMessages(messages, 19)) != 1 || len(fitMessages(messages, 34)) != 1 || len(fitMessages(messages, 21)) != 2 {
+		t.Errorf("Expected a lap, an activity and start/stop events, got %v", messages)
+	}
+}
+
+func TestWriteSessionFIT(t *testing.T) {
+	start := time.Date(2024, 1, 6, 20, 0, 0, 0, time.UTC)
+	session := go_oura.Session{
+		ID:            "1",
+		Type:          "breathing",
+		StartDatetime: start,
+		EndDatetime:   start.Add(3 * time.Minute),
+		HeartRateData: go_oura.SessionDataItems{Interval: 60, Items: []float64{61, 0, 58}, Timestamp: start, Gaps: []bool{false, true, false}},
+	}
+
+	var buf bytes.Buffer
+	if err := go_oura.WriteSessionFIT(&buf, session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages, err := decodeFIT(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records := fitMessages(messages, 20)
+	if len(records) != 2 {
+		t.Fatalf("Expected null samples to be skipped, got %d records", len(records))
+	}
+	if records[1].fields[253] != uint32(start.Add(2*time.Minute).Unix()-fitEpoch) || records[1].fields[3] != 58 {
+		t.Errorf("Unexpected record %v", records[1])
+	}
+
+	summary := fitMessages(messages, 18)[0].fields
+	if summary[11] != 0xFFFF || summary[9] != 0xFFFFFFFF || summary[5] != 0 {
+		t.Errorf("Expected invalid calories and distance for a generic session, got %v", summary)
+	}
+}
+
+func TestClient_WriteWorkoutFIT(t *testing.T) {
+	start := time.Date(2024, 1, 6, 14, 14, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(go_oura.HeartRates{Items: []go_oura.HeartRate{{Bpm: 120, Source: "workout", Timestamp: start.Add(time.Minute)}}})
+	}))
+	defer server.Close()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	workout := go_oura.Workout{Id: "1", Activity: "running", StartDatetime: start, EndDatetime: start.Add(10 * time.Minute)}
+
+	var buf bytes.Buffer
+	if err := client.WriteWorkoutFIT(&buf, workout); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages, err := decodeFIT(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fitMessages(messages, 20)) != 1 || fitMessages(messages, 18)[0].fields[5] != 1 {
+		t.Errorf("Expected a running session with 1 record, got %v", messages)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var heartRateJoinZones = []go_oura.HeartRateZone{
+	{Name: "easy", MinBpm: 0, MaxBpm: 120},
+	{Name: "hard", MinBpm: 120},
+}
+
+func TestHeartRateJoiner_JoinWorkouts(t *testing.T) {
+	start := time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC)
+	at := func(minutes float64) time.Time { return start.Add(time.Duration(minutes * float64(time.Minute))) }
+
+	workouts := []go_oura.Workout{
+		{Id: "late", StartDatetime: at(60), EndDatetime: at(70)},
+		{Id: "early", StartDatetime: at(0), EndDatetime: at(10)},
+		{Id: "empty", StartDatetime: at(200), EndDatetime: at(210)},
+	}
+	heartRates := []go_oura.HeartRate{
+		{Bpm: 150, Source: go_oura.HeartRateSourceWorkout, Timestamp: at(5)},
+		{Bpm: 100, Source: go_oura.HeartRateSourceWorkout, Timestamp: at(0)},
+		{Bpm: 60, Source: go_oura.HeartRateSourceRest, Timestamp: at(7)},
+		{Bpm: 140, Source: go_oura.HeartRateSourceLive, Timestamp: at(9)},
+		{Bpm: 110, Source: go_oura.HeartRateSourceAwake, Timestamp: at(11)},
+		{Bpm: 130, Source: go_oura.HeartRateSourceWorkout, Timestamp: at(65)},
+		{Bpm: 90, Source: go_oura.HeartRateSourceAwake, Timestamp: at(80)},
+	}
+
+	joined := go_oura.NewHeartRateJoiner(heartRateJoinZones).JoinWorkouts(workouts, heartRates)
+	if len(joined) != 3 || joined[0].Id != "late" || joined[1].Id != "early" || joined[2].Id != "empty" {
+		t.Fatalf("Expected the workouts in their original order, got %v", joined)
+	}
+
+	early := joined[1].HeartRate
+	if len(early.Samples) != 3 {
+		t.Fatalf("Expected 3 workout and live samples, got %v", early.Samples)
+	}
+	if early.Min != 100 || early.Max != 150 || early.Average != 130 {
+		t.Errorf("Expected min 100, max 150 and average 130, got %v, %v and %v", early.Min, early.Max, early.Average)
+	}
+	if early.Recovery == nil || *early.Recovery != 30 {
+		t.Errorf("Expected a recovery of 30, got %v", early.Recovery)
+	}
+	if early.TimeInZone["easy"] != 5*time.Minute || early.TimeInZone["hard"] != 5*time.Minute {
+		t.Errorf("Expected 5m easy and 5m hard, got %v", early.TimeInZone)
+	}
+
+	late := joined[0].HeartRate
+	if late.Recovery != nil {
+		t.Errorf("Expected no recovery without a sample in the recovery window, got %v", *late.Recovery)
+	}
+	if late.TimeInZone["hard"] != 5*time.Minute {
+		t.Errorf("Expected 5m hard, got %v", late.TimeInZone)
+	}
+
+	empty := joined[2].HeartRate
+	if len(empty.Samples) != 0 || empty.Average != 0 || empty.Recovery != nil {
+		t.Errorf("Expected an empty summary, got %v", empty)
+	}
+}
+
+func TestHeartRateJoiner_JoinSessions(t *testing.T) {
+	start := time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC)
+	sessions := []go_oura.Session{{ID: "s", StartDatetime: start, EndDatetime: start.Add(10 * time.Minute)}}
+	heartRates := []go_oura.HeartRate{
+		{Bpm: 60, Source: go_oura.HeartRateSourceSession, Timestamp: start},
+		{Bpm: 160, Source: go_oura.HeartRateSourceWorkout, Timestamp: start.Add(time.Minute)},
+		{Bpm: 58, Source: go_oura.HeartRateSourceSession, Timestamp: start.Add(2 * time.Minute)},
+	}
+
+	joiner := go_oura.NewHeartRateJoiner(heartRateJoinZones)
+	joiner.MaxSampleGap = 3 * time.Minute
+
+	joined := joiner.JoinSessions(sessions, heartRates)
+	summary := joined[0].HeartRate
+	if len(summary.Samples) != 2 || summary.Max != 60 || summary.Min != 58 {
+		t.Errorf("Expected the 2 session samples, got %v", summary.Samples)
+	}
+	if summary.TimeInZone["easy"] != 5*time.Minute {
+		t.Errorf("Expected 5m easy with the last sample capped, got %v", summary.TimeInZone)
+	}
+}
+
+func TestClient_GetWorkoutHeartRates(t *testing.T) {
+	start := time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case go_oura.WorkoutUrl:
+			_ = json.NewEncoder(rw).Encode(go_oura.Workouts{Items: []go_oura.Workout{
+				{Id: "w", StartDatetime: start, EndDatetime: start.Add(10 * time.Minute)},
+			}})
+		case go_oura.HeartRateUrl:
+			_ = json.NewEncoder(rw).Encode(go_oura.HeartRates{Items: []go_oura.HeartRate{
+				{Bpm: 125, Source: go_oura.HeartRateSourceWorkout, Timestamp: start.Add(time.Minute)},
+			}})
+		default:
+			t.Errorf("Unexpected request %s", req.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	joined, err := client.GetWorkoutHeartRates(start, start.AddDate(0, 0, 1), heartRateJoinZones)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(joined) != 1 || joined[0].HeartRate.Max != 125 || joined[0].HeartRate.TimeInZone["hard"] != 5*time.Minute {
+		t.Errorf("Expected the workout with its heart rate, got %v", joined)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"testing"
+	"time"
+)
+
+func TestEstimateMaxHeartRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		formula  go_oura.MaxHeartRateFormula
+		expected int
+	}{
+		{"Fox", go_oura.MaxHeartRateFox, 180},
+		{"Tanaka", go_oura.MaxHeartRateTanaka, 180},
+		{"Gellish", go_oura.MaxHeartRateGellish, 179},
+		{"Nes", go_oura.MaxHeartRateNes, 185},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxBpm, err := go_oura.PersonalInfo{Age: 40}.MaxHeartRate(tt.formula)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if maxBpm != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, maxBpm)
+			}
+		})
+	}
+
+	if _, err := go_oura.EstimateMaxHeartRate(0, go_oura.MaxHeartRateFox); err == nil {
+		t.Errorf("Expected error for a missing age")
+	}
+	if _, err := go_oura.EstimateMaxHeartRate(40, go_oura.MaxHeartRateFormula(99)); err == nil {
+		t.Errorf("Expected error for an unknown formula")
+	}
+}
+
+func TestHeartRateZones(t *testing.T) {
+	zones, err := go_oura.HeartRateZones(200)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []go_oura.HeartRateZone{
+		{Name: "zone 1", MinBpm: 100, MaxBpm: 120},
+		{Name: "zone 2", MinBpm: 120, MaxBpm: 140},
+		{Name: "zone 3", MinBpm: 140, MaxBpm: 160},
+		{Name: "zone 4", MinBpm: 160, MaxBpm: 180},
+		{Name: "zone 5", MinBpm: 180},
+	}
+	if len(zones) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, zones)
+	}
+	for i := range expected {
+		if zones[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], zones[i])
+		}
+	}
+
+	karvonen, err := go_oura.KarvonenHeartRateZones(180, 60)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if karvonen[0].MinBpm != 120 || karvonen[0].MaxBpm != 132 || karvonen[4].MinBpm != 168 {
+		t.Errorf("Expected zones from 120 through 168, got %v", karvonen)
+	}
+
+	if _, err := go_oura.KarvonenHeartRateZones(60, 60); err == nil {
+		t.Errorf("Expected error for a resting heart rate at the max")
+	}
+}
+
+func TestRestingHeartRate(t *testing.T) {
+	sleeps := []go_oura.Sleep{
+		{LowestHeartRate: 52, Type: go_oura.SleepTypeLongSleep},
+		{LowestHeartRate: 0, Type: go_oura.SleepTypeLongSleep},
+		{LowestHeartRate: 30, Type: go_oura.SleepTypeDeleted},
+		{LowestHeartRate: 55, Type: go_oura.SleepTypeLongSleep},
+		{LowestHeartRate: 48, Type: go_oura.SleepTypeSleep},
+		{LowestHeartRate: 60, Type: go_oura.SleepTypeLongSleep},
+	}
+
+	resting, ok := go_oura.RestingHeartRate(sleeps)
+	if !ok || resting != 54 {
+		t.Errorf("Expected %v, got %v", 54, resting)
+	}
+
+	if _, ok := go_oura.RestingHeartRate(nil); ok {
+		t.Errorf("Expected no resting heart rate without sleeps")
+	}
+}
+
+func TestBucketHeartRates(t *testing.T) {
+	zones := []go_oura.HeartRateZone{{Name: "low", MinBpm: 100, MaxBpm: 140}, {Name: "high", MinBpm: 140}}
+	start := time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC)
+
+	heartRates := []go_oura.HeartRate{
+		{Bpm: 150, Timestamp: start.Add(2 * time.Minute)},
+		{Bpm: 120, Timestamp: start},
+		{Bpm: 90, Timestamp: start.Add(3 * time.Minute)},
+		{Bpm: 130, Timestamp: start.Add(20 * time.Minute)},
+	}
+
+	report := go_oura.BucketHeartRates(heartRates, zones, 5*time.Minute)
+	if report.Zones[0].Duration != 7*time.Minute || report.Zones[1].Duration != time.Minute {
+		t.Errorf("Expected 7m low and 1m high, got %v", report.Zones)
+	}
+	if report.Outside != 5*time.Minute || report.Total != 13*time.Minute {
+		t.Errorf("Expected 5m outside of 13m, got %v of %v", report.Outside, report.Total)
+	}
+	if percent := report.Zones[1].Percent; percent < 7.69 || percent > 7.7 {
+		t.Errorf("Expected %v, got %v", 7.69, percent)
+	}
+}
+
+func TestBucketIntervalItems(t *testing.T) {
+	zones := []go_oura.HeartRateZone{{Name: "low", MaxBpm: 60}, {Name: "high", MinBpm: 60}}
+	items := go_oura.IntervalItems{Interval: 60, Items: []float64{58, 0, 61, 62}, Timestamp: time.Now(), Gaps: []bool{false, true, false, false}}
+
+	report := go_oura.BucketIntervalItems(items, zones)
+	if report.Zones[0].Duration != time.Minute || report.Zones[1].Duration != 2*time.Minute || report.Total != 3*time.Minute {
+		t.Errorf("Expected 1m low and 2m high of 3m, got %v", report)
+	}
+	if report.Zones[1].Percent < 66.6 || report.Zones[1].Percent > 66.7 {
+		t.Errorf("Expected %v, got %v", 66.67, report.Zones[1].Percent)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeHypnogram(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+
+	hypnogram, err := go_oura.DecodeHypnogram("4221134", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	minute := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+	expected := go_oura.Hypnogram{
+		{Start: minute(0), End: minute(5), Stage: go_oura.SleepStageAwake},
+		{Start: minute(5), End: minute(15), Stage: go_oura.SleepStageLight},
+		{Start: minute(15), End: minute(25), Stage: go_oura.SleepStageDeep},
+		{Start: minute(25), End: minute(30), Stage: go_oura.SleepStageREM},
+		{Start: minute(30), End: minute(35), Stage: go_oura.SleepStageAwake},
+	}
+	if !reflect.DeepEqual(hypnogram, expected) {
+		t.Errorf("Expected %v, got %v", expected, hypnogram)
+	}
+
+	if onset, ok := hypnogram.SleepOnset(); !ok || !onset.Equal(minute(5)) {
+		t.Errorf("Expected onset %v, got %v", minute(5), onset)
+	}
+	if awakening, ok := hypnogram.FinalAwakening(); !ok || !awakening.Equal(minute(30)) {
+		t.Errorf("Expected final awakening %v, got %v", minute(30), awakening)
+	}
+	if len(hypnogram.Transitions()) != 4 || hypnogram.Transitions()[1].From != go_oura.SleepStageLight || hypnogram.Transitions()[1].To != go_oura.SleepStageDeep {
+		t.Errorf("Unexpected transitions %v", hypnogram.Transitions())
+	}
+	if stage, ok := hypnogram.StageAt(minute(16)); !ok || stage != go_oura.SleepStageDeep {
+		t.Errorf("Expected deep sleep at 16 minutes, got %v", stage)
+	}
+}
+
+func TestDecodeHypnogram_Invalid(t *testing.T) {
+	if _, err := go_oura.DecodeHypnogram("1250", time.Now()); err == nil {
+		t.Errorf("Expected error for an invalid sleep phase")
+	}
+}
+
+func TestHypnogram_WASO(t *testing.T) {
+	tests := []struct {
+		name     string
+		phases   string
+		expected time.Duration
+	}{
+		{name: "wake in the middle", phases: "44224422344", expected: 10 * time.Minute},
+		{name: "no wake", phases: "4222", expected: 0},
+		{name: "never asleep", phases: "444", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hypnogram, err := go_oura.DecodeHypnogram(tt.phases, time.Now())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if hypnogram.WASO() != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, hypnogram.WASO())
+			}
+		})
+	}
+}
+
+func TestSleep_Hypnogram(t *testing.T) {
+	location := time.FixedZone("", -5*60*60)
+	sleep := go_oura.Sleep{
+		AwakeTime:          6650,
+		BedtimeStart:       time.Date(2024, 1, 21, 1, 28, 28, 0, location),
+		BedtimeEnd:         time.Date(2024, 1, 21, 9, 8, 18, 0, location),
+		DeepSleepDuration:  2850,
+		LightSleepDuration: 12690,
+		RemSleepDuration:   5400,
+		SleepPhase5Min:     "42222221222221122222444412211112212233332232222223344444424222222334343334444444422223222334",
+	}
+
+	hypnogram, err := sleep.Hypnogram()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !hypnogram[len(hypnogram)-1].End.Equal(sleep.BedtimeEnd) {
+		t.Errorf("Expected the hypnogram to end at %v, got %v", sleep.BedtimeEnd, hypnogram[len(hypnogram)-1].End)
+	}
+
+	durations := hypnogram.StageDurations()
+	if durations[go_oura.SleepStageDeep] != 45*time.Minute || durations[go_oura.SleepStageREM] != 80*time.Minute {
+		t.Errorf("Unexpected stage durations %v", durations)
+	}
+
+	if mismatches := hypnogram.CheckDurations(sleep, 10*time.Minute); len(mismatches) != 0 {
+		t.Errorf("Expected no mismatches within 10 minutes, got %v", mismatches)
+	}
+
+	mismatches := hypnogram.CheckDurations(sleep, 5*time.Minute)
+	if len(mismatches) != 2 || mismatches[0].Stage != go_oura.SleepStageLight || mismatches[1].Stage != go_oura.SleepStageREM {
+		t.Errorf("Expected light and REM mismatches within 5 minutes, got %v", mismatches)
+	}
+}
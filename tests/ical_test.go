@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/austinmoody/go_oura"
+	"strings"
+	"testing"
+	"time"
+)
+
+func icalTestCalendar() *go_oura.ICalendar {
+	calendar := go_oura.NewICalendar("Oura")
+	calendar.Now = func() time.Time { return time.Date(2024, 1, 22, 12, 0, 0, 0, time.UTC) }
+	return calendar
+}
+
+func TestICalendar_WriteTo(t *testing.T) {
+	start := time.Date(2024, 1, 20, 23, 30, 0, 0, time.FixedZone("", -5*60*60))
+	day := go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}
+
+	calendar := icalTestCalendar()
+	calendar.AddSleeps([]go_oura.Sleep{
+		{ID: "s1", Type: "long_sleep", BedtimeStart: start, BedtimeEnd: start.Add(8 * time.Hour), TotalSleepDuration: 27000, Efficiency: 88},
+		{ID: "s2", Type: go_oura.SleepTypeDeleted, BedtimeStart: start, BedtimeEnd: start.Add(time.Hour)},
+	})
+	calendar.AddWorkouts([]go_oura.Workout{
+		{Id: "w1", Activity: "walking", StartDatetime: start, EndDatetime: start.Add(time.Hour)},
+	})
+	calendar.AddEnhancedTags([]go_oura.EnhancedTag{
+		{ID: "t1", TagTypeCode: "tag_generic_nocaffeine", StartDay: &day, Comment: "coffee; none, really"},
+	})
+
+	var buf bytes.Buffer
+	if _, err := calendar.WriteTo(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.HasPrefix(output, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(output, "END:VCALENDAR\r\n") {
+		t.Errorf("Expected a CRLF delimited VCALENDAR, got:\n%s", output)
+	}
+	if strings.Count(output, "BEGIN:VEVENT") != 3 {
+		t.Errorf("Expected 3 events, got:\n%s", output)
+	}
+
+	expected := []string{
+		"UID:sleep-s1@go_oura\r\nDTSTAMP:20240122T120000Z\r\nDTSTART:20240121T043000Z\r\nDTEND:20240121T123000Z\r\nSUMMARY:Sleep\r\n",
+		`DESCRIPTION:Total sleep: 7h 30m\nDeep: 0h 00m`,
+		"UID:workout-w1@go_oura",
+		"SUMMARY:Walking\r\n",
+		"DTSTART;VALUE=DATE:20240121\r\nDTEND;VALUE=DATE:20240122\r\nSUMMARY:Nocaffeine\r\n",
+		`DESCRIPTION:coffee\; none\, really`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestICalendar_StableUIDs(t *testing.T) {
+	start := time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	write := func(label string) string {
+		calendar := icalTestCalendar()
+		calendar.AddWorkouts([]go_oura.Workout{{Id: "w1", Activity: "cycling", Label: label, StartDatetime: start, EndDatetime: start.Add(time.Hour)}})
+		var buf bytes.Buffer
+		if _, err := calendar.WriteTo(&buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	first, second := write("Ride"), write("Long ride")
+	if !strings.Contains(first, "UID:workout-w1@go_oura") || !strings.Contains(second, "UID:workout-w1@go_oura") {
+		t.Errorf("Expected the same UID for an updated workout, got:\n%s\n%s", first, second)
+	}
+}
+
+func TestICalendar_Sequence(t *testing.T) {
+	write := func(now time.Time, event go_oura.ICalendarEvent) string {
+		calendar := icalTestCalendar()
+		calendar.Now = func() time.Time { return now }
+		calendar.Events = append(calendar.Events, event)
+		var buf bytes.Buffer
+		if _, err := calendar.WriteTo(&buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	now := time.Date(2024, 1, 22, 12, 0, 0, 0, time.UTC)
+	event := go_oura.ICalendarEvent{UID: "e1", Summary: "Event", Start: now, End: now.Add(time.Hour)}
+
+	// Exporting again does not claim the event was revised.
+	if output := write(now.Add(time.Hour), event); strings.Contains(output, "SEQUENCE") || strings.Contains(output, "LAST-MODIFIED") {
+		t.Errorf("Expected no revision properties unless set, got:\n%s", output)
+	}
+
+	event.Sequence = 3
+	event.LastModified = now.Add(-time.Hour)
+	if output := write(now, event); !strings.Contains(output, "LAST-MODIFIED:20240122T110000Z\r\nSEQUENCE:3\r\n") {
+		t.Errorf("Expected the event's own sequence and last modified time, got:\n%s", output)
+	}
+}
+
+func TestICalendar_AddSleepTimes(t *testing.T) {
+	calendar := icalTestCalendar()
+	calendar.AddSleepTimes([]go_oura.SleepTime{
+		{
+			ID:             "st1",
+			Day:            go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			OptimalBedtime: &go_oura.OptimalBedtime{DayTz: -18000, StartOffset: -3600, EndOffset: 1800},
+			Recommendation: "follow_optimal_bedtime",
+		},
+		{ID: "st2", Day: go_oura.Date{Time: time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)}},
+	})
+
+	if len(calendar.Events) != 1 {
+		t.Fatalf("Expected 1 suggested event, got %d", len(calendar.Events))
+	}
+
+	event := calendar.Events[0]
+	expectedStart := time.Date(2024, 1, 21, 4, 0, 0, 0, time.UTC)
+	if !event.Start.Equal(expectedStart) || !event.End.Equal(expectedStart.Add(90*time.Minute)) || !event.Suggested {
+		t.Errorf("Unexpected suggested event %+v", event)
+	}
+
+	var buf bytes.Buffer
+	if _, err := calendar.WriteTo(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "STATUS:TENTATIVE\r\nTRANSP:TRANSPARENT\r\n") {
+		t.Errorf("Expected a tentative, transparent event, got:\n%s", buf.String())
+	}
+}
+
+func TestICalendar_Folding(t *testing.T) {
+	calendar := icalTestCalendar()
+	calendar.AddRestModes([]go_oura.RestMode{
+		{
+			ID:        "r1",
+			StartDay:  go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			StartTime: time.Date(2024, 1, 21, 8, 0, 0, 0, time.UTC),
+			Episodes: []go_oura.Episode{
+				{Tags: []string{strings.Repeat("ö", 60), "fever", "headache"}, Timestamp: time.Date(2024, 1, 21, 8, 0, 0, 0, time.UTC)},
+			},
+		},
+	})
+
+	var buf bytes.Buffer
+	if _, err := calendar.WriteTo(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines of at most 75 octets, got %d: %q", len(line), line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:2024-01-21T08:00:00Z: "+strings.Repeat("ö", 60)+"\\, fever\\, headache\r\n") {
+		t.Errorf("Expected the description to unfold intact, got:\n%s", unfolded)
+	}
+	if !strings.Contains(unfolded, "DTSTART;VALUE=DATE:20240121\r\nDTEND;VALUE=DATE:20240122\r\n") {
+		t.Errorf("Expected an ongoing rest mode as an all day event, got:\n%s", unfolded)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"strings"
+	"testing"
+)
+
+func illnessNights() []go_oura.Night {
+	var nights []go_oura.Night
+	offsets := []int{0, 2, -2, 0, 1, -1, 0, 2, -2}
+	for i, offset := range offsets {
+		nights = append(nights, go_oura.Night{
+			Day:            baselineDay(i + 1),
+			MainSleep:      &go_oura.Sleep{LowestHeartRate: 50 + offset, AverageHrv: 60 + offset, AverageBreath: 14 + float64(offset)/2},
+			DailyReadiness: &go_oura.DailyReadiness{TemperatureDeviation: 0.1},
+		})
+	}
+	return nights
+}
+
+func TestIllnessDetector_Detect(t *testing.T) {
+	nights := append(illnessNights(),
+		go_oura.Night{
+			Day:            baselineDay(10),
+			MainSleep:      &go_oura.Sleep{LowestHeartRate: 53, AverageHrv: 56, AverageBreath: 14},
+			DailyReadiness: &go_oura.DailyReadiness{TemperatureDeviation: 0.6},
+		},
+		go_oura.Night{
+			Day:            baselineDay(11),
+			MainSleep:      &go_oura.Sleep{LowestHeartRate: 80, AverageHrv: 20, AverageBreath: 20},
+			DailyReadiness: &go_oura.DailyReadiness{TemperatureDeviation: 1.5},
+		},
+		go_oura.Night{
+			Day:       baselineDay(12),
+			MainSleep: &go_oura.Sleep{LowestHeartRate: 50, AverageHrv: 60, AverageBreath: 14},
+		},
+	)
+	restModes := []go_oura.RestMode{{StartDay: baselineDay(11), EndDay: baselineDay(11)}}
+
+	risks := go_oura.NewIllnessDetector().Detect(nights, restModes)
+	if len(risks) != 12 {
+		t.Fatalf("Expected 12 risks, got %d", len(risks))
+	}
+
+	for _, risk := range risks[:9] {
+		if risk.Alert || risk.Excluded || len(risk.Signals) != 0 {
+			t.Errorf("Expected no signals on %v, got %v", risk.Day, risk.Signals)
+		}
+	}
+
+	sick := risks[9]
+	if !sick.Alert || len(sick.Signals) != 3 {
+		t.Fatalf("Expected an alert with 3 signals, got %v", sick)
+	}
+	expected := []go_oura.IllnessSignal{go_oura.IllnessSignalHrv, go_oura.IllnessSignalTemperature, go_oura.IllnessSignalRestingHeartRate}
+	for i, signal := range expected {
+		if sick.Signals[i].Signal != signal {
+			t.Errorf("Expected %v, got %v", signal, sick.Signals[i].Signal)
+		}
+	}
+	if sick.Score < 3.5 || sick.Score > 3.6 {
+		t.Errorf("Expected a score of about 3.57, got %v", sick.Score)
+	}
+	if explanation := sick.Explanation(); !strings.HasPrefix(explanation, "hrv 2.7 deviations below baseline; temperature +0.60 °C") {
+		t.Errorf("Unexpected explanation %q", explanation)
+	}
+
+	if !risks[10].Excluded || risks[10].Score != 0 {
+		t.Errorf("Expected the rest mode day to be excluded, got %v", risks[10])
+	}
+	if risks[11].Excluded || risks[11].Alert {
+		t.Errorf("Expected no alert after the rest mode day, got %v", risks[11])
+	}
+}
+
+func TestIllnessDetector_DetectOngoingRestMode(t *testing.T) {
+	restModes := []go_oura.RestMode{{StartDay: baselineDay(5)}}
+
+	risks := go_oura.NewIllnessDetector().Detect(illnessNights(), restModes)
+	for i, risk := range risks {
+		if excluded := i >= 4; risk.Excluded != excluded {
+			t.Errorf("Expected excluded %v on %v, got %v", excluded, risk.Day, risk.Excluded)
+		}
+	}
+}
+
+func TestIllnessDetector_Thresholds(t *testing.T) {
+	nights := []go_oura.Night{{Day: baselineDay(1), DailyReadiness: &go_oura.DailyReadiness{TemperatureDeviation: 0.4, TemperatureTrendDeviation: 0.2}}}
+
+	detector := go_oura.NewIllnessDetector()
+	if risks := detector.Detect(nights, nil); len(risks[0].Signals) != 0 {
+		t.Errorf("Expected no signals with the default thresholds, got %v", risks[0].Signals)
+	}
+
+	detector.Thresholds.TemperatureDeviation = 0.2
+	detector.Thresholds.TemperatureTrendDeviation = 0.2
+	risks := detector.Detect(nights, nil)
+	if len(risks[0].Signals) != 2 || !risks[0].Alert || risks[0].Signals[0].Score != 2 {
+		t.Errorf("Expected an alert from both temperatures with the first capped at 2, got %v", risks[0])
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/austinmoody/go_oura"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInfluxEncoder_EncodeHeartRates(t *testing.T) {
+	timestamp := time.Date(2024, 1, 10, 1, 45, 45, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := go_oura.NewInfluxEncoder(&buf, "austin moody").EncodeHeartRates([]go_oura.HeartRate{
+		{Bpm: 74, Source: "awake", Timestamp: timestamp},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `heart_rate,source=awake,user=austin\ moody bpm=74i 1704851145000000000` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestInfluxEncoder_EncodeSleeps(t *testing.T) {
+	start := time.Date(2024, 1, 21, 6, 28, 28, 0, time.UTC)
+	sleeps := []go_oura.Sleep{
+		{
+			ID:           "1",
+			Type:         "long_sleep",
+			BedtimeStart: start,
+			HeartRate:    go_oura.IntervalItems{Interval: 300, Items: []float64{64, 0, 60}, Timestamp: start, Gaps: []bool{false, true, false}},
+			Hrv:          go_oura.IntervalItems{Interval: 300, Items: []float64{30}, Timestamp: start},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := go_oura.NewInfluxEncoder(&buf, "").EncodeSleeps(sleeps); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected summary, 2 heart rate and 1 hrv lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	expected := []string{
+		"sleep_heart_rate,sleep_type=long_sleep bpm=64 1705818508000000000",
+		"sleep_heart_rate,sleep_type=long_sleep bpm=60 1705819108000000000",
+		"sleep_hrv,sleep_type=long_sleep hrv=30 1705818508000000000",
+	}
+	for i, line := range expected {
+		if lines[i+1] != line {
+			t.Errorf("Expected %q, got %q", line, lines[i+1])
+		}
+	}
+	if !strings.HasPrefix(lines[0], "sleep,sleep_type=long_sleep average_breath=0,") {
+		t.Errorf("Unexpected summary line %q", lines[0])
+	}
+}
+
+func TestInfluxEncoder_EncodeDailyStresses(t *testing.T) {
+	var buf bytes.Buffer
+	err := go_oura.NewInfluxEncoder(&buf, "austin").EncodeDailyStresses([]go_oura.DailyStress{
+		{ID: "1", Day: go_oura.Date{Time: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)}, StressHigh: 900, RecoveryHigh: 4500, DaySummary: `say "normal"`},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `daily_stress,user=austin day_summary="say \"normal\"",recovery_high=4500i,stress_high=900i 1704844800000000000` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
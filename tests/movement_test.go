@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"testing"
+	"time"
+)
+
+func TestDecodeMovement(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+
+	movement, err := go_oura.DecodeMovement("1123", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(movement) != 4 || !movement[3].Timestamp.Equal(start.Add(90*time.Second)) || movement[3].Level != go_oura.MovementTossing {
+		t.Errorf("Unexpected movement %v", movement)
+	}
+
+	if _, err := go_oura.DecodeMovement("15", start); err == nil {
+		t.Errorf("Expected error for an invalid movement level")
+	}
+}
+
+func TestMovement_RestlessEpisodes(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+	movement, err := go_oura.DecodeMovement("1121113431112", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		level    go_oura.MovementLevel
+		minimum  time.Duration
+		expected []go_oura.RestlessEpisode
+	}{
+		{
+			name:    "restless",
+			level:   go_oura.MovementRestless,
+			minimum: 0,
+			expected: []go_oura.RestlessEpisode{
+				{Start: start.Add(60 * time.Second), End: start.Add(90 * time.Second), Peak: go_oura.MovementRestless},
+				{Start: start.Add(180 * time.Second), End: start.Add(270 * time.Second), Peak: go_oura.MovementActive},
+				{Start: start.Add(360 * time.Second), End: start.Add(390 * time.Second), Peak: go_oura.MovementRestless},
+			},
+		},
+		{
+			name:    "at least a minute",
+			level:   go_oura.MovementRestless,
+			minimum: time.Minute,
+			expected: []go_oura.RestlessEpisode{
+				{Start: start.Add(180 * time.Second), End: start.Add(270 * time.Second), Peak: go_oura.MovementActive},
+			},
+		},
+		{
+			name:    "active only",
+			level:   go_oura.MovementActive,
+			minimum: 0,
+			expected: []go_oura.RestlessEpisode{
+				{Start: start.Add(210 * time.Second), End: start.Add(240 * time.Second), Peak: go_oura.MovementActive},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			episodes := movement.RestlessEpisodes(tt.level, tt.minimum)
+			if len(episodes) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, episodes)
+			}
+			for i := range episodes {
+				if !episodes[i].Start.Equal(tt.expected[i].Start) || !episodes[i].End.Equal(tt.expected[i].End) || episodes[i].Peak != tt.expected[i].Peak {
+					t.Errorf("Expected %v, got %v", tt.expected[i], episodes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMovement_HourlySummaries(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 59, 0, 0, time.FixedZone("", 5*60*60+30*60))
+	movement, err := go_oura.DecodeMovement("13111", start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hours := movement.HourlySummaries()
+	if len(hours) != 2 {
+		t.Fatalf("Expected 2 hours, got %d", len(hours))
+	}
+	if !hours[0].Start.Equal(start.Add(-59*time.Minute)) || hours[0].Samples != 2 || hours[0].AverageLevel != 2 {
+		t.Errorf("Unexpected first hour %+v", hours[0])
+	}
+	if hours[1].Samples != 3 || hours[1].AverageLevel != 1 || hours[1].Levels[go_oura.MovementStill] != 90*time.Second {
+		t.Errorf("Unexpected second hour %+v", hours[1])
+	}
+}
+
+func TestSleep_AlignedMovement(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+	sleep := go_oura.Sleep{
+		BedtimeStart:   start,
+		BedtimeEnd:     start.Add(10 * time.Minute),
+		Movement30Sec:  "11111111112222222222",
+		SleepPhase5Min: "21",
+		HeartRate:      go_oura.IntervalItems{Interval: 300, Items: []float64{0, 58}, Timestamp: start},
+	}
+
+	aligned, err := sleep.AlignedMovement()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(aligned) != 20 {
+		t.Fatalf("Expected 20 samples, got %d", len(aligned))
+	}
+	if aligned[0].Stage != go_oura.SleepStageLight || aligned[0].HeartRate != 0 {
+		t.Errorf("Unexpected first sample %+v", aligned[0])
+	}
+	if aligned[10].Stage != go_oura.SleepStageDeep || aligned[10].HeartRate != 58 || aligned[10].Level != go_oura.MovementRestless {
+		t.Errorf("Unexpected sample at 5 minutes %+v", aligned[10])
+	}
+}
+
+func TestMovement_AlignHeartRateGap(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+	movement := go_oura.Movement{
+		{Timestamp: start, Level: go_oura.MovementStill},
+		{Timestamp: start.Add(5 * time.Minute), Level: go_oura.MovementStill},
+	}
+
+	var heartRate go_oura.IntervalItems
+	err := json.Unmarshal([]byte(`{"interval":300,"items":[null,58],"timestamp":"2024-01-21T01:00:00+00:00"}`), &heartRate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	aligned := movement.Align(nil, heartRate)
+	if aligned[0].HeartRateValid || aligned[0].HeartRate != 0 {
+		t.Errorf("Expected no heart rate for the null sample, got %+v", aligned[0])
+	}
+	if !aligned[1].HeartRateValid || aligned[1].HeartRate != 58 {
+		t.Errorf("Expected 58 after the null sample, got %+v", aligned[1])
+	}
+}
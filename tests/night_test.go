@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"testing"
+	"time"
+)
+
+func TestLinkNights(t *testing.T) {
+	day := func(d int) go_oura.Date { return go_oura.Date{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)} }
+
+	sleeps := []go_oura.Sleep{
+		{ID: "nap", Day: day(21), Period: 2, Type: go_oura.SleepTypeLateNap, TimeInBed: 1800, TotalSleepDuration: 1200},
+		{ID: "short", Day: day(21), Period: 0, Type: go_oura.SleepTypeLongSleep, TimeInBed: 7200, TotalSleepDuration: 6000},
+		{ID: "long", Day: day(21), Period: 1, Type: go_oura.SleepTypeLongSleep, TimeInBed: 21600, TotalSleepDuration: 19000},
+		{ID: "deleted", Day: day(21), Period: 3, Type: go_oura.SleepTypeDeleted, TimeInBed: 30000},
+		{ID: "rest", Day: day(22), Period: 0, Type: go_oura.SleepTypeRest, TimeInBed: 3600},
+	}
+	dailySleeps := []go_oura.DailySleep{{ID: "ds21", Day: day(21), Score: 80}}
+	readinesses := []go_oura.DailyReadiness{{Id: "r23", Day: day(23), Score: 70}}
+	sleepTimes := []go_oura.SleepTime{{ID: "st21", Day: day(21)}}
+
+	nights := go_oura.LinkNights(sleeps, dailySleeps, readinesses, sleepTimes)
+	if len(nights) != 3 {
+		t.Fatalf("Expected 3 nights, got %d", len(nights))
+	}
+	for i, d := range []int{21, 22, 23} {
+		if !nights[i].Day.Equal(day(d).Time) {
+			t.Errorf("Expected night %d on 2024-01-%d, got %v", i, d, nights[i].Day)
+		}
+	}
+
+	first := nights[0]
+	if first.MainSleep == nil || first.MainSleep.ID != "long" {
+		t.Errorf("Expected the longest long_sleep as main sleep, got %v", first.MainSleep)
+	}
+	// The night is split, so the shorter long_sleep is not a nap.
+	if len(first.SplitSleeps) != 1 || first.SplitSleeps[0].ID != "short" {
+		t.Errorf("Expected the other long_sleep as a split sleep, got %v", first.SplitSleeps)
+	}
+	if len(first.Naps) != 1 || first.Naps[0].ID != "nap" {
+		t.Errorf("Expected the late nap as the only nap, got %v", first.Naps)
+	}
+	periods := first.Periods()
+	if len(periods) != 3 || periods[0].ID != "short" || periods[1].ID != "long" || periods[2].ID != "nap" {
+		t.Errorf("Expected periods in period order, got %v", periods)
+	}
+	if first.TotalSleepDuration() != 26200 {
+		t.Errorf("Expected %v, got %v", 26200, first.TotalSleepDuration())
+	}
+	if first.DailySleep == nil || first.DailySleep.Score != 80 || first.SleepTime == nil || first.DailyReadiness != nil {
+		t.Errorf("Expected the daily sleep and sleep time only, got %v, %v and %v", first.DailySleep, first.SleepTime, first.DailyReadiness)
+	}
+
+	second := nights[1]
+	if second.MainSleep != nil || len(second.Naps) != 1 || second.Naps[0].ID != "rest" {
+		t.Errorf("Expected no main sleep and a single nap, got %v and %v", second.MainSleep, second.Naps)
+	}
+
+	third := nights[2]
+	if third.DailyReadiness == nil || third.DailyReadiness.Score != 70 || third.MainSleep != nil {
+		t.Errorf("Expected only a readiness, got %v", third)
+	}
+}
+
+func TestLinkNight(t *testing.T) {
+	day := go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}
+	snapshot := go_oura.DaySnapshot{
+		Day:            day,
+		DailyReadiness: &go_oura.DailyReadiness{Id: "r", Day: day, Score: 75},
+		Sleeps: []go_oura.Sleep{
+			{ID: "deleted", Day: day, Type: go_oura.SleepTypeDeleted},
+			{ID: "long", Day: day, Type: go_oura.SleepTypeLongSleep, TimeInBed: 20000},
+		},
+	}
+	sleepTime := &go_oura.SleepTime{ID: "st", Day: day}
+
+	night := go_oura.LinkNight(snapshot, sleepTime)
+	if night.MainSleep == nil || night.MainSleep.ID != "long" || len(night.Naps) != 0 {
+		t.Errorf("Expected the long sleep only, got %v and %v", night.MainSleep, night.Naps)
+	}
+	if night.DailyReadiness == nil || night.SleepTime != sleepTime || night.DailySleep != nil {
+		t.Errorf("Expected the readiness and sleep time, got %v", night)
+	}
+}
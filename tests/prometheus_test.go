@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func metricsTestServer(t *testing.T, requests *int64) *httptest.Server {
+	day := go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}
+	previousDay := go_oura.Date{Time: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)}
+
+	responses := map[string]any{
+		go_oura.ReadinessUrl: go_oura.DailyReadinesses{Items: []go_oura.DailyReadiness{
+			{Id: "old", Day: previousDay, Score: 50},
+			{Id: "new", Day: day, Score: 61, TemperatureDeviation: -0.09,
+				Contributors: go_oura.ReadinessContributors{HrvBalance: 55}},
+		}},
+		go_oura.DailySleepUrl: go_oura.DailySleeps{Items: []go_oura.DailySleep{{ID: "1", Day: day, Score: 83}}},
+		go_oura.ActivityUrl:   go_oura.DailyActivities{Items: []go_oura.DailyActivity{{ID: "1", Day: day, Score: 96, Steps: 10234}}},
+		go_oura.Spo2Url:       go_oura.DailySpo2Readings{Items: []go_oura.DailySpo2Reading{{ID: "1", Day: day, Percentage: go_oura.Spo2Percentage{Average: 98.5}}}},
+		go_oura.StressUrl:     go_oura.DailyStresses{Items: []go_oura.DailyStress{{ID: "1", Day: day, StressHigh: 3600}}},
+		go_oura.SleepUrl: go_oura.Sleeps{Items: []go_oura.Sleep{
+			{ID: "main", Day: day, Type: "long_sleep", LowestHeartRate: 52, AverageHrv: 48, TotalSleepDuration: 25000},
+			{ID: "nap", Day: day, Type: "sleep", LowestHeartRate: 70, AverageHrv: 20, TotalSleepDuration: 1800},
+		}},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt64(requests, 1)
+
+		response, ok := responses[req.URL.Path]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(rw).Encode(response); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}))
+}
+
+func TestMetricsExporter_Refresh(t *testing.T) {
+	var requests int64
+	server := metricsTestServer(t, &requests)
+	defer server.Close()
+
+	exporter := go_oura.NewMetricsExporter(go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), "austin")
+	exporter.Now = func() time.Time { return time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC) }
+
+	if err := exporter.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	refreshRequests := atomic.LoadInt64(&requests)
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+
+	if atomic.LoadInt64(&requests) != refreshRequests {
+		t.Errorf("Expected scrape to be served from cache without API requests")
+	}
+
+	expected := []string{
+		"# TYPE oura_readiness_score gauge",
+		`oura_readiness_score{user="austin"} 61`,
+		`oura_readiness_contributor{contributor="hrv_balance",user="austin"} 55`,
+		`oura_temperature_deviation_celsius{user="austin"} -0.09`,
+		`oura_sleep_score{user="austin"} 83`,
+		`oura_activity_score{user="austin"} 96`,
+		`oura_steps{user="austin"} 10234`,
+		`oura_resting_heart_rate_bpm{user="austin"} 52`,
+		`oura_hrv_milliseconds{user="austin"} 48`,
+		`oura_spo2_percent{user="austin"} 98.5`,
+		`oura_stress_high_seconds{user="austin"} 3600`,
+		`oura_exporter_refresh_success{user="austin"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsExporter_ZeroValue(t *testing.T) {
+	var requests int64
+	server := metricsTestServer(t, &requests)
+	defer server.Close()
+
+	exporter := &go_oura.MetricsExporter{Client: go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), User: "austin"}
+	exporter.Now = func() time.Time { return time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC) }
+
+	if err := exporter.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A zero Interval falls back to DefaultMetricsInterval rather than panicking.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := exporter.Run(ctx); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), `oura_readiness_score{user="austin"} 61`+"\n") {
+		t.Errorf("Expected the readiness score, got:\n%s", recorder.Body.String())
+	}
+}
+
+func TestMetricsExporter_RefreshCancelledInFlight(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	exporter := go_oura.NewMetricsExporter(go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), "austin")
+	done := make(chan error, 1)
+	go func() { done <- exporter.Refresh(ctx) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Expected error for a cancelled context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected cancelling the context to stop the request in flight")
+	}
+}
+
+func TestMetricsExporter_BeforeRefresh(t *testing.T) {
+	exporter := go_oura.NewMetricsExporter(go_oura.NewClient(""), `quote"user`)
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expected := "# HELP oura_exporter_refresh_success Whether the last refresh from the Oura API succeeded.\n" +
+		"# TYPE oura_exporter_refresh_success gauge\n" +
+		`oura_exporter_refresh_success{user="quote\"user"} 0` + "\n"
+	if recorder.Body.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, recorder.Body.String())
+	}
+}
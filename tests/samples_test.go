@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntervalItems_Samples(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, time.UTC)
+	items := go_oura.IntervalItems{Interval: 300, Items: []float64{64, 0, 0}, Timestamp: start, Gaps: []bool{false, true, false}}
+
+	expected := []go_oura.Sample{
+		{Time: start, Value: 64, Valid: true},
+		{Time: start.Add(5 * time.Minute), Value: 0, Valid: false},
+		{Time: start.Add(10 * time.Minute), Value: 0, Valid: true},
+	}
+	if samples := items.Samples(); !reflect.DeepEqual(samples, expected) {
+		t.Errorf("Expected %v, got %v", expected, samples)
+	}
+
+	if valid := items.ValidSamples(); len(valid) != 2 || valid[1].Value != 0 {
+		t.Errorf("Expected 2 valid samples keeping the zero reading, got %v", valid)
+	}
+
+	met := go_oura.Met{Interval: 60, Items: []float64{1.2}, Timestamp: start}
+	if samples := met.Samples(); len(samples) != 1 || !samples[0].Valid {
+		t.Errorf("Unexpected met samples %v", samples)
+	}
+}
+
+func TestIntervalItems_JSON(t *testing.T) {
+	var session go_oura.SessionDataItems
+	if err := json.Unmarshal([]byte(`{"interval":5,"items":[0,27,null],"timestamp":"2023-03-13T16:25:11-04:00"}`), &session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	samples := session.Samples()
+	if !samples[0].Valid || samples[0].Value != 0 || samples[2].Valid {
+		t.Errorf("Expected a zero reading and a gap, got %v", samples)
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `{"interval":5,"items":[0,27,null],"timestamp":"2023-03-13T16:25:11-04:00"}`; string(data) != expected {
+		t.Errorf("Expected %v, got %v", expected, string(data))
+	}
+
+	var items go_oura.IntervalItems
+	if err := json.Unmarshal([]byte(`{"interval":60,"items":[1,2],"timestamp":"2023-03-13T16:25:11-04:00"}`), &items); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items.Gaps != nil {
+		t.Errorf("Expected no gaps, got %v", items.Gaps)
+	}
+}
+
+func TestIntervalItems_Between(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+	items := go_oura.IntervalItems{Interval: 300, Items: []float64{1, 2, 0, 4, 5}, Timestamp: start, Gaps: []bool{false, false, true, false, false}}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		expected go_oura.IntervalItems
+	}{
+		{
+			name:     "middle",
+			start:    start.Add(4 * time.Minute),
+			end:      start.Add(15 * time.Minute),
+			expected: go_oura.IntervalItems{Interval: 300, Items: []float64{2, 0}, Timestamp: start.Add(5 * time.Minute), Gaps: []bool{false, true}},
+		},
+		{
+			name:     "all",
+			start:    start.Add(-time.Hour),
+			end:      start.Add(time.Hour),
+			expected: go_oura.IntervalItems{Interval: 300, Items: []float64{1, 2, 0, 4, 5}, Timestamp: start, Gaps: []bool{false, false, true, false, false}},
+		},
+		{
+			name:     "none",
+			start:    start.Add(time.Hour),
+			end:      start.Add(2 * time.Hour),
+			expected: go_oura.IntervalItems{Interval: 300, Items: []float64{}, Timestamp: start.Add(25 * time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			between := items.Between(tt.start, tt.end)
+			if between.Interval != tt.expected.Interval || !between.Timestamp.Equal(tt.expected.Timestamp) || len(between.Items) != len(tt.expected.Items) {
+				t.Fatalf("Expected %v, got %v", tt.expected, between)
+			}
+			for i := range between.Items {
+				if between.Items[i] != tt.expected.Items[i] || between.IsGap(i) != tt.expected.IsGap(i) {
+					t.Errorf("Expected %v, got %v", tt.expected, between)
+				}
+			}
+		})
+	}
+}
+
+func TestIntervalItems_Resample(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		items    go_oura.IntervalItems
+		interval float64
+		expected []float64
+		gaps     []bool
+	}{
+		{
+			name:     "downsample",
+			items:    go_oura.IntervalItems{Interval: 60, Items: []float64{60, 62, 0, 0, 70}, Timestamp: start, Gaps: []bool{false, false, true, true, false}},
+			interval: 120,
+			expected: []float64{61, 0, 70},
+			gaps:     []bool{false, true, false},
+		},
+		{
+			name:     "upsample",
+			items:    go_oura.IntervalItems{Interval: 300, Items: []float64{60, 0}, Timestamp: start, Gaps: []bool{false, true}},
+			interval: 150,
+			expected: []float64{60, 60, 0, 0},
+			gaps:     []bool{false, false, true, true},
+		},
+		{
+			name:     "unaligned",
+			items:    go_oura.IntervalItems{Interval: 60, Items: []float64{60, 90}, Timestamp: start},
+			interval: 90,
+			expected: []float64{70, 90},
+		},
+		{
+			name:     "zero reading",
+			items:    go_oura.IntervalItems{Interval: 60, Items: []float64{0, 4}, Timestamp: start},
+			interval: 120,
+			expected: []float64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resampled, err := tt.items.Resample(tt.interval)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resampled.Interval != tt.interval || !resampled.Timestamp.Equal(start) || !reflect.DeepEqual(resampled.Items, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, resampled.Items)
+			}
+			if !reflect.DeepEqual(resampled.Gaps, tt.gaps) {
+				t.Errorf("Expected gaps %v, got %v", tt.gaps, resampled.Gaps)
+			}
+		})
+	}
+
+	if _, err := (go_oura.IntervalItems{Interval: 60}).Resample(0); err == nil {
+		t.Errorf("Expected error for an invalid interval")
+	}
+}
+
+func TestMergeIntervalItems(t *testing.T) {
+	start := time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC)
+	first := go_oura.IntervalItems{Interval: 300, Items: []float64{1, 2}, Timestamp: start}
+	second := go_oura.IntervalItems{Interval: 300, Items: []float64{3}, Timestamp: start.Add(20 * time.Minute)}
+
+	merged, err := go_oura.MergeIntervalItems(second, first)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := go_oura.IntervalItems{Interval: 300, Items: []float64{1, 2, 0, 0, 3}, Timestamp: start, Gaps: []bool{false, false, true, true, false}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	if _, err := go_oura.MergeIntervalItems(first, go_oura.IntervalItems{Interval: 300, Items: []float64{9}, Timestamp: start.Add(5 * time.Minute)}); err == nil {
+		t.Errorf("Expected error for overlapping series")
+	}
+	if _, err := go_oura.MergeIntervalItems(go_oura.IntervalItems{Items: []float64{1}, Timestamp: start}); err == nil {
+		t.Errorf("Expected error for a zero interval")
+	}
+	if _, err := go_oura.MergeIntervalItems(first, go_oura.IntervalItems{Interval: 60, Timestamp: start.Add(time.Hour)}); err == nil {
+		t.Errorf("Expected error for different intervals")
+	}
+}
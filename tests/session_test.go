@@ -42,6 +42,7 @@ func TestGetSessionDocument(t *testing.T) {
 				HeartRateData: go_oura.SessionDataItems{
 					Interval: 5,
 					Items:    []float64{0, 57.8, 58},
+					Gaps:     []bool{true, false, false},
 					Timestamp: func() time.Time {
 						layout := "2006-01-02T15:04:05Z07:00"
 						t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -51,6 +52,7 @@ func TestGetSessionDocument(t *testing.T) {
 				HeartRateVariabilityData: go_oura.SessionDataItems{
 					Interval: 5,
 					Items:    []float64{0, 31, 31.5},
+					Gaps:     []bool{true, false, false},
 					Timestamp: func() time.Time {
 						layout := "2006-01-02T15:04:05Z07:00"
 						t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -61,6 +63,7 @@ func TestGetSessionDocument(t *testing.T) {
 				MotionCountData: go_oura.SessionDataItems{
 					Interval: 5,
 					Items:    []float64{0, 27, 0},
+					Gaps:     []bool{false, false, true},
 					Timestamp: func() time.Time {
 						layout := "2006-01-02T15:04:05Z07:00"
 						t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -147,6 +150,7 @@ func TestGetSessions(t *testing.T) {
 						HeartRateData: go_oura.SessionDataItems{
 							Interval: 5,
 							Items:    []float64{0, 57.8, 58},
+							Gaps:     []bool{true, false, false},
 							Timestamp: func() time.Time {
 								layout := "2006-01-02T15:04:05Z07:00"
 								t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -156,6 +160,7 @@ func TestGetSessions(t *testing.T) {
 						HeartRateVariabilityData: go_oura.SessionDataItems{
 							Interval: 5,
 							Items:    []float64{0, 31, 31.5},
+							Gaps:     []bool{true, false, false},
 							Timestamp: func() time.Time {
 								layout := "2006-01-02T15:04:05Z07:00"
 								t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -166,6 +171,7 @@ func TestGetSessions(t *testing.T) {
 						MotionCountData: go_oura.SessionDataItems{
 							Interval: 5,
 							Items:    []float64{0, 27, 0},
+							Gaps:     []bool{false, false, true},
 							Timestamp: func() time.Time {
 								layout := "2006-01-02T15:04:05Z07:00"
 								t, _ := time.Parse(layout, "2023-03-13T16:25:11.000-04:00")
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// metricsNight returns a night on 2024-01-d with a main sleep from start to end, given as hours after midnight of
+// the night's day, asleep throughout.
+func metricsNight(d int, start float64, end float64) go_oura.Night {
+	day := baselineDay(d)
+	bedtimeStart := day.Add(time.Duration(start * float64(time.Hour)))
+	bedtimeEnd := day.Add(time.Duration(end * float64(time.Hour)))
+	phases := strings.Repeat("1", int(bedtimeEnd.Sub(bedtimeStart)/go_oura.SleepPhaseInterval))
+
+	return go_oura.Night{Day: day, MainSleep: &go_oura.Sleep{
+		Day:                day,
+		Type:               go_oura.SleepTypeLongSleep,
+		BedtimeStart:       bedtimeStart,
+		BedtimeEnd:         bedtimeEnd,
+		SleepPhase5Min:     phases,
+		TotalSleepDuration: int(bedtimeEnd.Sub(bedtimeStart).Seconds()),
+	}}
+}
+
+func TestSleepDebt(t *testing.T) {
+	withNap := metricsNight(3, -1, 7)
+	withNap.Naps = []go_oura.Sleep{{TotalSleepDuration: 5400}}
+
+	nights := []go_oura.Night{metricsNight(2, -1, 6), metricsNight(1, -1, 6), withNap, metricsNight(4, -1, 7)}
+	days := go_oura.SleepDebt(nights, 8*time.Hour)
+
+	expected := []time.Duration{time.Hour, 2 * time.Hour, 30 * time.Minute, 30 * time.Minute}
+	for i, debt := range expected {
+		if !days[i].Day.Equal(baselineDay(i + 1).Time) {
+			t.Errorf("Expected day %v, got %v", baselineDay(i+1), days[i].Day)
+		}
+		if days[i].Debt != debt {
+			t.Errorf("Expected debt %v on day %d, got %v", debt, i+1, days[i].Debt)
+		}
+	}
+	if days[2].Slept != 9*time.Hour+30*time.Minute || days[2].Balance != 90*time.Minute {
+		t.Errorf("Expected 9h30m slept including the nap, got %v", days[2].Slept)
+	}
+}
+
+func TestSleepRegularityIndex(t *testing.T) {
+	nights := []go_oura.Night{
+		metricsNight(1, -1, 7),
+		metricsNight(2, -1, 7),
+		metricsNight(3, 0, 8),
+		// Day 4 is missing, so day 5 is not compared with day 3.
+		metricsNight(5, 4, 12),
+	}
+
+	sri, ok := go_oura.SleepRegularityIndex(nights)
+	if !ok {
+		t.Fatalf("Expected a Sleep Regularity Index")
+	}
+	expected := -100 + 200*552.0/576.0
+	if math.Abs(sri-expected) > 1e-9 {
+		t.Errorf("Expected %v, got %v", expected, sri)
+	}
+
+	if _, ok := go_oura.SleepRegularityIndex(nights[3:]); ok {
+		t.Errorf("Expected no Sleep Regularity Index without consecutive nights")
+	}
+}
+
+func TestNight_Midpoint(t *testing.T) {
+	night := metricsNight(1, -1, 7)
+	night.MainSleep.SleepPhase5Min = "44" + night.MainSleep.SleepPhase5Min[2:]
+
+	midpoint, ok := night.Midpoint()
+	if !ok || !midpoint.Equal(baselineDay(1).Add(3*time.Hour+5*time.Minute)) {
+		t.Errorf("Expected the midpoint from sleep onset, got %v", midpoint)
+	}
+
+	if _, ok := (go_oura.Night{}).Midpoint(); ok {
+		t.Errorf("Expected no midpoint without a main sleep")
+	}
+}
+
+func TestComputeSocialJetlag(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	nights := []go_oura.Night{
+		metricsNight(1, -1, 7),
+		metricsNight(2, -5, 3),
+		metricsNight(6, 0.5, 8.5),
+		metricsNight(7, 0.5, 8.5),
+	}
+
+	jetlag, ok := go_oura.ComputeSocialJetlag(nights)
+	if !ok {
+		t.Fatalf("Expected social jetlag")
+	}
+	if jetlag.WorkdayMidpoint != time.Hour || jetlag.FreeDayMidpoint != 4*time.Hour+30*time.Minute {
+		t.Errorf("Expected midpoints of 1h and 4h30m, got %v and %v", jetlag.WorkdayMidpoint, jetlag.FreeDayMidpoint)
+	}
+	if jetlag.Jetlag != 3*time.Hour+30*time.Minute || jetlag.WorkdayNights != 2 || jetlag.FreeDayNights != 2 {
+		t.Errorf("Expected 3h30m jetlag over 2 and 2 nights, got %v", jetlag)
+	}
+
+	if _, ok := go_oura.ComputeSocialJetlag(nights[:2]); ok {
+		t.Errorf("Expected no social jetlag without free days")
+	}
+}
+
+func TestBedtimeAdherences(t *testing.T) {
+	window := &go_oura.OptimalBedtime{DayTz: 0, StartOffset: -3600, EndOffset: 0}
+	nights := []go_oura.Night{
+		metricsNight(3, -2, 6),
+		metricsNight(1, -1, 7),
+		metricsNight(2, 0.5, 8),
+		metricsNight(4, -1, 7),
+	}
+	for i := range nights[:3] {
+		nights[i].SleepTime = &go_oura.SleepTime{Day: nights[i].Day, OptimalBedtime: window}
+	}
+
+	adherences := go_oura.BedtimeAdherences(nights)
+	if len(adherences) != 3 {
+		t.Fatalf("Expected 3 adherences, got %v", adherences)
+	}
+	expected := []struct {
+		within bool
+		offset time.Duration
+	}{
+		{true, 0},
+		{false, 30 * time.Minute},
+		{false, -time.Hour},
+	}
+	for i, e := range expected {
+		if adherences[i].Within != e.within || adherences[i].Offset != e.offset {
+			t.Errorf("Expected within %v and offset %v on day %d, got %v", e.within, e.offset, i+1, adherences[i])
+		}
+	}
+}
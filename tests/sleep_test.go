@@ -70,6 +70,7 @@ func TestGetSleep(t *testing.T) {
 						64,
 						0,
 					},
+					Gaps: []bool{false, true},
 				},
 				Hrv: go_oura.IntervalItems{
 					Interval: 300,
@@ -86,6 +87,7 @@ func TestGetSleep(t *testing.T) {
 						0,
 						15,
 					},
+					Gaps: []bool{false, false, true, false, true, false},
 				},
 				Readiness: go_oura.SleepReadiness{
 					Contributors: go_oura.Contributors{
@@ -210,6 +212,7 @@ func TestGetSleeps(t *testing.T) {
 								64,
 								0,
 							},
+							Gaps: []bool{false, true},
 						},
 						Hrv: go_oura.IntervalItems{
 							Interval: 300,
@@ -226,6 +229,7 @@ func TestGetSleeps(t *testing.T) {
 								0,
 								15,
 							},
+							Gaps: []bool{false, false, true, false, true, false},
 						},
 						Readiness: go_oura.SleepReadiness{
 							Contributors: go_oura.Contributors{
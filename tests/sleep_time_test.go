@@ -165,3 +165,49 @@ func TestGetSleepTimes(t *testing.T) {
 		})
 	}
 }
+
+func TestSleepTime_BedtimeWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		sleepTime     go_oura.SleepTime
+		expectedOk    bool
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			name: "window the evening before",
+			sleepTime: go_oura.SleepTime{
+				Day:            go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+				OptimalBedtime: &go_oura.OptimalBedtime{DayTz: -18000, StartOffset: -3600, EndOffset: 1800},
+				Recommendation: go_oura.SleepTimeFollowOptimalBedtime,
+				Status:         go_oura.SleepTimeOptimalFound,
+			},
+			expectedOk:    true,
+			expectedStart: "2024-01-20T23:00:00-05:00",
+			expectedEnd:   "2024-01-21T00:30:00-05:00",
+		},
+		{
+			name: "no optimal bedtime",
+			sleepTime: go_oura.SleepTime{
+				Day:    go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+				Status: go_oura.SleepTimeNotEnoughNights,
+			},
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := tt.sleepTime.BedtimeWindow()
+			if ok != tt.expectedOk {
+				t.Fatalf("Expected %v, got %v", tt.expectedOk, ok)
+			}
+			if !ok {
+				return
+			}
+			if start.Format(time.RFC3339) != tt.expectedStart || end.Format(time.RFC3339) != tt.expectedEnd {
+				t.Errorf("Expected %s - %s, got %s - %s", tt.expectedStart, tt.expectedEnd, start.Format(time.RFC3339), end.Format(time.RFC3339))
+			}
+		})
+	}
+}
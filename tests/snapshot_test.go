@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_GetDays(t *testing.T) {
+	day := func(d int) go_oura.Date { return go_oura.Date{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)} }
+	tagDay := day(21)
+	tagEndDay := day(22)
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		var response any
+		switch req.URL.Path {
+		case go_oura.DailySleepUrl:
+			response = go_oura.DailySleeps{Items: []go_oura.DailySleep{{ID: "ds21", Day: day(21), Score: 80}, {ID: "ds23", Day: day(23)}}}
+		case go_oura.ReadinessUrl:
+			response = go_oura.DailyReadinesses{Items: []go_oura.DailyReadiness{{Id: "r22", Day: day(22), Score: 75}}}
+		case go_oura.SleepUrl:
+			response = go_oura.Sleeps{Items: []go_oura.Sleep{
+				{ID: "nap", Day: day(21), Type: go_oura.SleepTypeLateNap, BedtimeStart: time.Date(2024, 1, 21, 15, 0, 0, 0, time.UTC)},
+				{ID: "long", Day: day(21), Type: go_oura.SleepTypeLongSleep, BedtimeStart: time.Date(2024, 1, 20, 23, 0, 0, 0, time.UTC)},
+			}}
+		case go_oura.TagUrl:
+			response = go_oura.EnhancedTags{Items: []go_oura.EnhancedTag{{ID: "t", StartDay: &tagDay, EndDay: &tagEndDay}}}
+		case go_oura.WorkoutUrl:
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		case go_oura.ActivityUrl:
+			response = go_oura.DailyActivities{}
+		case go_oura.Spo2Url:
+			response = go_oura.DailySpo2Readings{}
+		case go_oura.StressUrl:
+			response = go_oura.DailyStresses{}
+		case go_oura.SessionUrl:
+			response = go_oura.Sessions{}
+		default:
+			t.Errorf("Unexpected request %s", req.URL.Path)
+		}
+		_ = json.NewEncoder(rw).Encode(response)
+	}))
+	defer server.Close()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	snapshots, err := client.GetDays(context.Background(), day(21), day(22))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(snapshots) != 2 || !snapshots[0].Day.Equal(day(21).Time) || !snapshots[1].Day.Equal(day(22).Time) {
+		t.Fatalf("Expected snapshots for 2024-01-21 and 2024-01-22, got %v", snapshots)
+	}
+
+	first, second := snapshots[0], snapshots[1]
+	if first.DailySleep == nil || first.DailySleep.Score != 80 || second.DailySleep != nil {
+		t.Errorf("Expected a daily sleep on the first day only, got %v and %v", first.DailySleep, second.DailySleep)
+	}
+	if first.DailyReadiness != nil || second.DailyReadiness == nil || second.DailyReadiness.Score != 75 {
+		t.Errorf("Expected a readiness on the second day only, got %v and %v", first.DailyReadiness, second.DailyReadiness)
+	}
+	if len(first.Sleeps) != 2 || first.Sleeps[0].ID != "long" {
+		t.Errorf("Expected sleeps in bedtime order, got %v", first.Sleeps)
+	}
+	if len(first.EnhancedTags) != 1 || len(second.EnhancedTags) != 1 {
+		t.Errorf("Expected the tag on both days it spans, got %v and %v", first.EnhancedTags, second.EnhancedTags)
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Complete() || len(snapshot.Errors) != 1 || snapshot.Errors[go_oura.ResourceWorkout] == nil {
+			t.Errorf("Expected only the workout resource to fail, got %v", snapshot.Errors)
+		}
+	}
+
+	if maxActive > 4 {
+		t.Errorf("Expected at most 4 concurrent requests, got %d", maxActive)
+	}
+}
+
+func TestClient_GetDay_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %s", req.URL.Path)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	if _, err := client.GetDay(ctx, go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}); err == nil {
+		t.Errorf("Expected error for a cancelled context")
+	}
+}
+
+func TestClient_GetDays_CancelledInFlight(t *testing.T) {
+	started := make(chan struct{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+		_, err := client.GetDays(ctx, go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}, go_oura.Date{Time: time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected %v, got %v", context.Canceled, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected cancelling the context to stop requests in flight")
+	}
+}
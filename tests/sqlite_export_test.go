@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/austinmoody/go_oura"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingDriver is a minimal database/sql driver which records executed statements.  It understands just enough
+// of the schema_migrations table to answer the exporter's version query.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+	version    int64
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+func (d *recordingDriver) count(prefix string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := 0
+	for _, statement := range d.statements {
+		if strings.HasPrefix(statement, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{driver: c.driver, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingStmt struct {
+	driver *recordingDriver
+	query  string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+
+	s.driver.statements = append(s.driver.statements, s.query)
+	if strings.HasPrefix(s.query, "INSERT INTO schema_migrations") {
+		s.driver.version = args[0].(int64)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+
+	return &versionRows{version: s.driver.version}, nil
+}
+
+type versionRows struct {
+	version int64
+	done    bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version"} }
+func (r *versionRows) Close() error      { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.version
+	return nil
+}
+
+var recordingDriverCount int
+
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	recordingDriverCount++
+	name := fmt.Sprintf("recording%d", recordingDriverCount)
+
+	d := &recordingDriver{}
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, d
+}
+
+func TestSQLiteExporter_Migrate(t *testing.T) {
+	db, d := openRecordingDB(t)
+	ctx := context.Background()
+
+	exporter := go_oura.NewSQLiteExporter(db)
+	for i := 0; i < 2; i++ {
+		if err := exporter.Migrate(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if n := d.count("INSERT INTO schema_migrations"); n != exporter.SchemaVersion() {
+		t.Errorf("Expected %d migrations recorded, got %d", exporter.SchemaVersion(), n)
+	}
+	if n := d.count("CREATE TABLE sleep "); n != 1 {
+		t.Errorf("Expected sleep table created once, got %d", n)
+	}
+}
+
+func TestSQLiteExporter_Export(t *testing.T) {
+	db, d := openRecordingDB(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, time.UTC)
+	sleeps := go_oura.Sleeps{
+		Items: []go_oura.Sleep{
+			{
+				ID:        "1",
+				Day:       go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+				HeartRate: go_oura.IntervalItems{Interval: 300, Items: []float64{64, 62, 60}, Timestamp: start},
+				Hrv:       go_oura.IntervalItems{Interval: 300, Items: []float64{30, 13}, Timestamp: start},
+			},
+		},
+	}
+
+	exporter := go_oura.NewSQLiteExporter(db)
+	for i := 0; i < 2; i++ {
+		if err := exporter.Export(ctx, sleeps, go_oura.HeartRate{Bpm: 60, Source: "rest", Timestamp: start}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	tt := []struct {
+		prefix   string
+		expected int
+	}{
+		{"INSERT OR REPLACE INTO sleep ", 2},
+		{"DELETE FROM sleep_sample WHERE sleep_id = ?", 2},
+		{"INSERT INTO sleep_sample", 10},
+		{"DELETE FROM sleep_readiness_contributor WHERE sleep_id = ?", 2},
+		{"INSERT INTO sleep_readiness_contributor", 16},
+		{"INSERT OR REPLACE INTO heart_rate", 2},
+	}
+
+	for _, tc := range tt {
+		if n := d.count(tc.prefix); n != tc.expected {
+			t.Errorf("Expected %d statements starting %q, got %d", tc.expected, tc.prefix, n)
+		}
+	}
+}
+
+func TestSQLiteExporter_UnsupportedDocument(t *testing.T) {
+	db, _ := openRecordingDB(t)
+
+	if err := go_oura.NewSQLiteExporter(db).Export(context.Background(), 42); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestSQLiteExporter_MissingDay(t *testing.T) {
+	db, _ := openRecordingDB(t)
+
+	if err := go_oura.NewSQLiteExporter(db).Export(context.Background(), go_oura.DailySleep{ID: "1"}); err == nil {
+		t.Errorf("Expected error for a document without a day")
+	}
+}
@@ -0,0 +1,118 @@
+//go:build sqlite
+
+// These tests run the SQLite exporter against a real SQLite driver, which needs cgo.  Run them with:
+// go test -tags sqlite ./tests/
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"github.com/austinmoody/go_oura"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openRealSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Every connection to :memory: opens a new database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteExporter_RealExport(t *testing.T) {
+	db := openRealSQLite(t)
+	ctx := context.Background()
+
+	plus2 := time.FixedZone("", 2*60*60)
+	start := time.Date(2024, 1, 21, 1, 28, 28, 0, plus2)
+	day := go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}
+
+	documents := []any{
+		go_oura.Sleeps{Items: []go_oura.Sleep{{
+			ID:           "1",
+			Day:          day,
+			Type:         go_oura.SleepTypeLongSleep,
+			BedtimeStart: start,
+			BedtimeEnd:   start.Add(8 * time.Hour),
+			HeartRate:    go_oura.IntervalItems{Interval: 300, Items: []float64{64, 0, 60}, Timestamp: start, Gaps: []bool{false, true, false}},
+		}}},
+		go_oura.HeartRate{Bpm: 60, Source: "rest", Timestamp: start},
+		go_oura.HeartRate{Bpm: 95, Source: "workout", Timestamp: start},
+		go_oura.HeartRate{Bpm: 58, Source: "rest", Timestamp: time.Date(2024, 1, 20, 23, 30, 0, 0, time.UTC)},
+		go_oura.DailyStress{ID: "2", Day: day, StressHigh: 3600, DaySummary: "normal"},
+		go_oura.Workout{Id: "3", Day: day, Activity: "walking", StartDatetime: start, EndDatetime: start.Add(time.Hour)},
+		go_oura.RestMode{ID: "4", StartDay: day, StartTime: start, Episodes: []go_oura.Episode{{Tags: []string{"tag_generic_sick"}, Timestamp: start}}},
+	}
+
+	exporter := go_oura.NewSQLiteExporter(db)
+	for i := 0; i < 2; i++ {
+		if err := exporter.Export(ctx, documents...); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	counts := []struct {
+		table    string
+		expected int
+	}{
+		{"sleep", 1},
+		{"sleep_sample", 3},
+		{"sleep_readiness_contributor", 8},
+		{"heart_rate", 3},
+		{"daily_stress", 1},
+		{"workout", 1},
+		{"rest_mode_period", 1},
+		{"rest_mode_episode", 1},
+		{"rest_mode_episode_tag", 1},
+		{"schema_migrations", exporter.SchemaVersion()},
+	}
+	for _, tc := range counts {
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+tc.table).Scan(&n); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if n != tc.expected {
+			t.Errorf("Expected %d rows in %s, got %d", tc.expected, tc.table, n)
+		}
+	}
+
+	var bedtimeStart string
+	if err := db.QueryRowContext(ctx, "SELECT bedtime_start FROM sleep").Scan(&bedtimeStart); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bedtimeStart != "2024-01-20T23:28:28.000Z" {
+		t.Errorf("Expected %v, got %v", "2024-01-20T23:28:28.000Z", bedtimeStart)
+	}
+
+	// Timestamps are comparable as TEXT whatever offset the API returned them with.
+	var first int
+	if err := db.QueryRowContext(ctx, "SELECT bpm FROM heart_rate ORDER BY timestamp, source LIMIT 1").Scan(&first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != 60 {
+		t.Errorf("Expected the 23:28 UTC heart rate first, got %v", first)
+	}
+
+	var gap sql.NullFloat64
+	if err := db.QueryRowContext(ctx, "SELECT value FROM sleep_sample WHERE sample_index = 1").Scan(&gap); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gap.Valid {
+		t.Errorf("Expected a NULL sample for the gap, got %v", gap.Float64)
+	}
+}
+
+func TestSQLiteExporter_RealMissingDay(t *testing.T) {
+	db := openRealSQLite(t)
+
+	err := go_oura.NewSQLiteExporter(db).Export(context.Background(), go_oura.DailySleep{ID: "1", Score: 80})
+	if err == nil {
+		t.Errorf("Expected error for a document without a day")
+	}
+}
@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func storeDate(day string) go_oura.Date {
+	t, _ := time.Parse("2006-01-02", day)
+	return go_oura.Date{Time: t}
+}
+
+func TestJSONLStore_UpsertAndQuery(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := go_oura.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stresses := go_oura.DailyStresses{
+		Items: []go_oura.DailyStress{
+			{ID: "a", Day: storeDate("2024-01-30"), StressHigh: 100, DaySummary: "normal"},
+			{ID: "b", Day: storeDate("2024-02-01"), StressHigh: 200, DaySummary: "stressful"},
+		},
+	}
+	if err := store.Upsert(ctx, stresses); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Oura re-scored day a, the new version should replace the old one.
+	if err := store.Upsert(ctx, go_oura.DailyStress{ID: "a", Day: storeDate("2024-01-30"), StressHigh: 150, DaySummary: "normal"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, partition := range []string{"2024-01.jsonl", "2024-02.jsonl"} {
+		if _, err := os.Stat(filepath.Join(dir, "daily_stress", partition)); err != nil {
+			t.Errorf("Expected partition %s, got %v", partition, err)
+		}
+	}
+
+	all, err := go_oura.QueryDocuments[go_oura.DailyStress](ctx, store, go_oura.StoreQuery{Resource: go_oura.ResourceDailyStress})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(all) != 2 || all[0].StressHigh != 150 || all[1].ID != "b" {
+		t.Errorf("Expected upserted documents, got %v", all)
+	}
+	if !reflect.DeepEqual(all[1], stresses.Items[1]) {
+		t.Errorf("Expected %v, got %v", stresses.Items[1], all[1])
+	}
+
+	february, err := store.Query(ctx, go_oura.StoreQuery{
+		Resource: go_oura.ResourceDailyStress,
+		StartDay: storeDate("2024-01-31").Time,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(february) != 1 || february[0].ID != "b" {
+		t.Errorf("Expected only document b, got %v", february)
+	}
+}
+
+func TestJSONLStore_HeartRateTimestampQuery(t *testing.T) {
+	ctx := context.Background()
+	store, err := go_oura.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Date(2024, 1, 10, 1, 0, 0, 0, time.UTC)
+	heartRates := go_oura.HeartRates{
+		Items: []go_oura.HeartRate{
+			{Bpm: 60, Source: "rest", Timestamp: start},
+			{Bpm: 62, Source: "rest", Timestamp: start.Add(5 * time.Minute)},
+			// A reading from another source at the same instant is kept alongside the first.
+			{Bpm: 95, Source: "workout", Timestamp: start.Add(5 * time.Minute)},
+			{Bpm: 64, Source: "rest", Timestamp: start.Add(10 * time.Minute)},
+		},
+	}
+	if err := store.Upsert(ctx, heartRates); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	found, err := go_oura.QueryDocuments[go_oura.HeartRate](ctx, store, go_oura.StoreQuery{
+		Resource: go_oura.ResourceHeartRate,
+		Start:    start.Add(time.Minute),
+		End:      start.Add(10 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(found) != 2 || found[0].Bpm != 62 || found[1].Bpm != 95 {
+		t.Errorf("Expected the 62 and 95 bpm samples, got %v", found)
+	}
+}
+
+func TestJSONLStore_Compact(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := go_oura.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := store.Upsert(ctx, go_oura.PersonalInfo{ID: "me", Age: 40 + i}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if err := store.Compact(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "personal_info", "undated.jsonl"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("Expected 1 line after compaction, got %d", lines)
+	}
+
+	info, err := go_oura.QueryDocuments[go_oura.PersonalInfo](ctx, store, go_oura.StoreQuery{Resource: go_oura.ResourcePersonalInfo})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(info) != 1 || info[0].Age != 42 {
+		t.Errorf("Expected latest personal info, got %v", info)
+	}
+}
+
+func TestJSONLStore_UpsertMovesPartition(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := go_oura.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	january := storeDate("2024-01-31")
+	february := storeDate("2024-02-01")
+	if err := store.Upsert(ctx, go_oura.EnhancedTag{ID: "tag", TagTypeCode: "tag_generic_alcohol", StartDay: &january}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The tag was edited to start a day later, moving it to the February partition.
+	if err := store.Upsert(ctx, go_oura.EnhancedTag{ID: "tag", TagTypeCode: "tag_generic_alcohol", StartDay: &february}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := go_oura.StoreQuery{Resource: go_oura.ResourceEnhancedTag}
+	tags, err := go_oura.QueryDocuments[go_oura.EnhancedTag](ctx, store, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tags) != 1 || !tags[0].StartDay.Equal(february.Time) {
+		t.Errorf("Expected only the moved tag, got %v", tags)
+	}
+
+	if err := store.Compact(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "enhanced_tag", "2024-01.jsonl"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("Expected an empty January partition, got %s", data)
+	}
+
+	// A new store over the same directory builds its index from the files.
+	reopened, err := go_oura.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	records, err := reopened.Query(ctx, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].Day != "2024-02-01" {
+		t.Errorf("Expected one record on 2024-02-01, got %v", records)
+	}
+}
+
+func TestJSONLStore_UpsertWriteFailure(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := go_oura.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	january := storeDate("2024-01-31")
+	february := storeDate("2024-02-01")
+	if err := store.Upsert(ctx, go_oura.EnhancedTag{ID: "tag", TagTypeCode: "tag_generic_alcohol", StartDay: &january}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A directory in place of the February partition makes the move fail.
+	if err := os.MkdirAll(filepath.Join(dir, "enhanced_tag", "2024-02.jsonl"), 0o755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Upsert(ctx, go_oura.EnhancedTag{ID: "tag", TagTypeCode: "tag_generic_alcohol", StartDay: &february}); err == nil {
+		t.Fatalf("Expected error writing the February partition")
+	}
+
+	records, err := store.Query(ctx, go_oura.StoreQuery{Resource: go_oura.ResourceEnhancedTag})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].Day != "2024-01-31" {
+		t.Errorf("Expected the January record to remain, got %v", records)
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	stress := go_oura.DailyStress{ID: "a", Day: storeDate("2024-01-30"), DaySummary: "normal"}
+
+	data, err := json.Marshal(stress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"day":"2024-01-30"`) {
+		t.Errorf("Expected the day as YYYY-MM-DD, got %s", data)
+	}
+
+	var decoded go_oura.DailyStress
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, stress) {
+		t.Errorf("Expected %v, got %v", stress, decoded)
+	}
+
+	data, err = json.Marshal(go_oura.Date{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != "null" {
+		t.Errorf("Expected null, got %s", data)
+	}
+	var zero go_oura.Date
+	if err := json.Unmarshal(data, &zero); err != nil || !zero.IsZero() {
+		t.Errorf("Expected a zero date, got %v with error %v", zero, err)
+	}
+}
+
+func TestJSONLStore_UnsupportedDocument(t *testing.T) {
+	store, err := go_oura.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := store.Upsert(context.Background(), "not a document"); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type memoryCheckpointStore map[go_oura.Resource]go_oura.Checkpoint
+
+func (m memoryCheckpointStore) LoadCheckpoint(resource go_oura.Resource) (*go_oura.Checkpoint, error) {
+	checkpoint, ok := m[resource]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (m memoryCheckpointStore) SaveCheckpoint(checkpoint go_oura.Checkpoint) error {
+	m[checkpoint.Resource] = checkpoint
+	return nil
+}
+
+func TestSyncer_ResumesAfterFailure(t *testing.T) {
+	failSecondPage := true
+	var tokens []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		token := req.URL.Query().Get("next_token")
+		tokens = append(tokens, token)
+
+		switch {
+		case token == "":
+			_, _ = rw.Write([]byte(`{"data":[{"id":"1","day":"2024-01-10","stress_high":100,"recovery_high":0,"day_summary":"normal"}],"next_token":"page2"}`))
+		case failSecondPage:
+			rw.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = rw.Write([]byte(`{"data":[{"id":"2","day":"2024-01-11","stress_high":200,"recovery_high":0,"day_summary":"stressful"}],"next_token":null}`))
+		}
+	}))
+	defer server.Close()
+
+	var written []any
+	sink := go_oura.SyncSinkFunc(func(ctx context.Context, resource go_oura.Resource, documents []any) error {
+		written = append(written, documents...)
+		return nil
+	})
+
+	checkpoints := memoryCheckpointStore{}
+	syncer := go_oura.NewSyncer(go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), checkpoints, sink)
+	syncer.Resources = []go_oura.Resource{go_oura.ResourceDailyStress}
+	syncer.Now = func() time.Time { return time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC) }
+
+	if err := syncer.Run(context.Background()); err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+
+	checkpoint := checkpoints[go_oura.ResourceDailyStress]
+	if checkpoint.NextToken == nil || *checkpoint.NextToken != "page2" {
+		t.Fatalf("Expected next token page2 in checkpoint, got %v", checkpoint.NextToken)
+	}
+	if !checkpoint.SyncedThrough.IsZero() {
+		t.Errorf("Expected unfinished sync, got synced through %v", checkpoint.SyncedThrough)
+	}
+
+	failSecondPage = false
+	if err := syncer.Run(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(written) != 2 {
+		t.Errorf("Expected 2 documents written, got %d", len(written))
+	}
+	if tokens[len(tokens)-1] != "page2" {
+		t.Errorf("Expected resumed request with next token page2, got %q", tokens[len(tokens)-1])
+	}
+
+	checkpoint = checkpoints[go_oura.ResourceDailyStress]
+	if checkpoint.NextToken != nil {
+		t.Errorf("Expected no next token, got %v", *checkpoint.NextToken)
+	}
+	if !checkpoint.SyncedThrough.Equal(time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected synced through 2024-01-12, got %v", checkpoint.SyncedThrough)
+	}
+}
+
+func TestSyncer_CancelledInFlight(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	sink := go_oura.SyncSinkFunc(func(context.Context, go_oura.Resource, []any) error { return nil })
+	syncer := go_oura.NewSyncer(go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), memoryCheckpointStore{}, sink)
+
+	done := make(chan error, 1)
+	go func() { done <- syncer.SyncResource(ctx, go_oura.ResourceDailyStress) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Expected error for a cancelled context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected cancelling the context to stop the request in flight")
+	}
+}
+
+func TestSyncer_Lookback(t *testing.T) {
+	var startDate, endDate string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		startDate = req.URL.Query().Get("start_date")
+		endDate = req.URL.Query().Get("end_date")
+		_, _ = rw.Write([]byte(`{"data":[],"next_token":null}`))
+	}))
+	defer server.Close()
+
+	checkpoints := memoryCheckpointStore{
+		go_oura.ResourceDailyStress: {
+			Resource:      go_oura.ResourceDailyStress,
+			SyncedThrough: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	sink := go_oura.SyncSinkFunc(func(ctx context.Context, resource go_oura.Resource, documents []any) error {
+		return nil
+	})
+
+	syncer := go_oura.NewSyncer(go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client()), checkpoints, sink)
+	syncer.Now = func() time.Time { return time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC) }
+
+	if err := syncer.SyncResource(context.Background(), go_oura.ResourceDailyStress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if startDate != "2024-01-07" || endDate != "2024-01-12" {
+		t.Errorf("Expected window 2024-01-07 - 2024-01-12, got %s - %s", startDate, endDate)
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store := go_oura.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+
+	checkpoint, err := store.LoadCheckpoint(go_oura.ResourceSleep)
+	if err != nil || checkpoint != nil {
+		t.Fatalf("Expected no checkpoint, got %v, %v", checkpoint, err)
+	}
+
+	token := "abc"
+	saved := go_oura.Checkpoint{
+		Resource:      go_oura.ResourceSleep,
+		SyncedThrough: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		NextToken:     &token,
+	}
+	if err := store.SaveCheckpoint(saved); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	checkpoint, err = store.LoadCheckpoint(go_oura.ResourceSleep)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkpoint == nil || !checkpoint.SyncedThrough.Equal(saved.SyncedThrough) || *checkpoint.NextToken != token {
+		t.Errorf("Expected %v, got %v", saved, checkpoint)
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"github.com/austinmoody/go_oura"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeTagImpact(t *testing.T) {
+	var nights []go_oura.Night
+	for i, score := range []int64{80, 70, 82, 68, 78, 84} {
+		day := baselineDay(i + 1)
+		nights = append(nights, go_oura.Night{
+			Day:        day,
+			MainSleep:  &go_oura.Sleep{Day: day, BedtimeStart: day.Add(-time.Hour)},
+			DailySleep: &go_oura.DailySleep{Day: day, Score: score},
+		})
+	}
+
+	at := func(d int, hour int) *time.Time {
+		t := time.Date(2024, 1, d, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	day := func(d int) *go_oura.Date {
+		date := baselineDay(d)
+		return &date
+	}
+	tags := []go_oura.EnhancedTag{
+		{TagTypeCode: "tag_generic_alcohol", StartDay: day(1)},
+		{TagTypeCode: "tag_generic_alcohol", StartDay: day(3), EndDay: day(3)},
+		{TagTypeCode: "tag_generic_sick", StartDay: day(4), EndDay: day(5)},
+		{TagTypeCode: "tag_generic_caffeine", StartDay: day(3), StartTime: at(3, 15)},
+		{TagTypeCode: "tag_generic_travel", StartDay: day(4), StartTime: at(4, 12), EndDay: day(5), EndTime: at(5, 22)},
+	}
+
+	effects := go_oura.AnalyzeTagImpact(tags, nights)
+	if len(effects) != 4 {
+		t.Fatalf("Expected an effect on the sleep score per tag type, got %v", effects)
+	}
+
+	expected := []struct {
+		code     string
+		tagged   int
+		untagged int
+		ok       bool
+	}{
+		{"tag_generic_alcohol", 2, 4, true},
+		{"tag_generic_caffeine", 1, 5, false},
+		{"tag_generic_sick", 2, 4, true},
+		{"tag_generic_travel", 2, 4, true},
+	}
+	for i, e := range expected {
+		effect := effects[i]
+		if effect.TagTypeCode != e.code || effect.Biomarker != go_oura.BiomarkerSleepScore {
+			t.Errorf("Expected %v sleep score, got %v %v", e.code, effect.TagTypeCode, effect.Biomarker)
+		}
+		if effect.TaggedDays != e.tagged || effect.UntaggedDays != e.untagged || effect.Ok != e.ok {
+			t.Errorf("Expected %v tagged and %v untagged days for %v, got %v", e.tagged, e.untagged, e.code, effect)
+		}
+	}
+
+	alcohol := effects[0]
+	if alcohol.TaggedMean != 69 || alcohol.UntaggedMean != 81 || alcohol.Difference != -12 {
+		t.Errorf("Expected means 69 and 81, got %v and %v", alcohol.TaggedMean, alcohol.UntaggedMean)
+	}
+	if d := -12 / math.Sqrt(5.5); math.Abs(alcohol.CohensD-d) > 1e-9 {
+		t.Errorf("Expected %v, got %v", d, alcohol.CohensD)
+	}
+	// Welch-Satterthwaite gives 3.69 degrees of freedom, so the t quantile lies between those for 3 and 4.
+	standardError := math.Sqrt(2.0/2 + (20.0/3)/4)
+	if margin := alcohol.Upper - alcohol.Difference; margin < 2.776445*standardError || margin > 3.182446*standardError {
+		t.Errorf("Expected a margin between %v and %v, got %v", 2.776445*standardError, 3.182446*standardError, margin)
+	}
+	if math.Abs((alcohol.Upper-alcohol.Difference)-(alcohol.Difference-alcohol.Lower)) > 1e-9 {
+		t.Errorf("Expected an interval centred on %v, got %v to %v", alcohol.Difference, alcohol.Lower, alcohol.Upper)
+	}
+
+	if effects[2].TaggedMean != 81 || effects[3].TaggedMean != 81 {
+		t.Errorf("Expected the multi day tags to cover nights 5 and 6, got %v and %v", effects[2], effects[3])
+	}
+}
+
+func TestAnalyzeTagImpact_NightAfterGap(t *testing.T) {
+	// Nights 4 and 5 are missing, so the first night after the tag starts two days later.
+	var nights []go_oura.Night
+	for _, d := range []int{1, 2, 3, 6, 7, 8} {
+		day := baselineDay(d)
+		nights = append(nights, go_oura.Night{
+			Day:        day,
+			MainSleep:  &go_oura.Sleep{Day: day, BedtimeStart: day.Add(-time.Hour)},
+			DailySleep: &go_oura.DailySleep{Day: day, Score: int64(70 + d)},
+		})
+	}
+
+	start := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	startDay := baselineDay(3)
+	effects := go_oura.AnalyzeTagImpact([]go_oura.EnhancedTag{{TagTypeCode: "tag_generic_caffeine", StartDay: &startDay, StartTime: &start}}, nights)
+	if len(effects) != 1 {
+		t.Fatalf("Expected only the sleep score effect, got %v", effects)
+	}
+	if effects[0].TaggedDays != 0 || effects[0].UntaggedDays != 6 {
+		t.Errorf("Expected no tagged night more than a day after the tag, got %v", effects[0])
+	}
+}
+
+func TestAnalyzeTagImpact_WelchInterval(t *testing.T) {
+	var nights []go_oura.Night
+	for i, minutes := range []int{60, 62, 70, 72} {
+		day := baselineDay(i + 1)
+		nights = append(nights, go_oura.Night{
+			Day:       day,
+			MainSleep: &go_oura.Sleep{Day: day, DeepSleepDuration: minutes * 60},
+		})
+	}
+	start, end := baselineDay(2), baselineDay(3)
+	effects := go_oura.AnalyzeTagImpact([]go_oura.EnhancedTag{{TagTypeCode: "tag_generic_late_meal", StartDay: &start, EndDay: &end}}, nights)
+	if len(effects) != 1 {
+		t.Fatalf("Expected only the deep sleep effect, got %v", effects)
+	}
+
+	// Equal groups of two with equal variances have 2 degrees of freedom.
+	effect := effects[0]
+	margin := 4.302653 * math.Sqrt(2.0/2+2.0/2)
+	if effect.Biomarker != go_oura.BiomarkerDeepSleep || effect.Difference != 10 {
+		t.Errorf("Expected a deep sleep difference of 10, got %v", effect)
+	}
+	if math.Abs(effect.Lower-(10-margin)) > 1e-5 || math.Abs(effect.Upper-(10+margin)) > 1e-5 {
+		t.Errorf("Expected interval %v to %v, got %v to %v", 10-margin, 10+margin, effect.Lower, effect.Upper)
+	}
+}
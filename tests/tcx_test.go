@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tcxDocument struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Id    string `xml:"Id"`
+		Lap   struct {
+			TotalTimeSeconds float64 `xml:"TotalTimeSeconds"`
+			DistanceMeters   float64 `xml:"DistanceMeters"`
+			Calories         int     `xml:"Calories"`
+			AverageHeartRate int     `xml:"AverageHeartRateBpm>Value"`
+			MaximumHeartRate int     `xml:"MaximumHeartRateBpm>Value"`
+			Trackpoints      []struct {
+				Time      string `xml:"Time"`
+				HeartRate int    `xml:"HeartRateBpm>Value"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+		Notes string `xml:"Notes"`
+	} `xml:"Activities>Activity"`
+}
+
+func TestWriteTCX(t *testing.T) {
+	start := time.Date(2024, 1, 6, 9, 14, 0, 0, time.FixedZone("", -5*60*60))
+	workout := go_oura.Workout{
+		Id:            "1",
+		Activity:      "running",
+		Calories:      29.627,
+		Distance:      4689.8,
+		StartDatetime: start,
+		EndDatetime:   start.Add(13 * time.Minute),
+		Label:         "Morning run",
+	}
+	heartRates := []go_oura.HeartRate{
+		{Bpm: 140, Source: "workout", Timestamp: start.Add(5 * time.Minute)},
+		{Bpm: 90, Source: "awake", Timestamp: start.Add(-5 * time.Minute)},
+		{Bpm: 120, Source: "workout", Timestamp: start.Add(time.Minute)},
+		{Bpm: 100, Source: "awake", Timestamp: start.Add(20 * time.Minute)},
+	}
+
+	var buf bytes.Buffer
+	if err := go_oura.WriteTCX(&buf, []go_oura.Workout{workout}, heartRates); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var document tcxDocument
+	if err := xml.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(document.Activities) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(document.Activities))
+	}
+	activity := document.Activities[0]
+	if activity.Sport != "Running" || activity.Id != "2024-01-06T14:14:00Z" || activity.Notes != "Morning run (running)" {
+		t.Errorf("Unexpected activity %+v", activity)
+	}
+	if activity.Lap.TotalTimeSeconds != 780 || activity.Lap.DistanceMeters != 4689.8 || activity.Lap.Calories != 30 {
+		t.Errorf("Unexpected lap summary %+v", activity.Lap)
+	}
+	if len(activity.Lap.Trackpoints) != 2 {
+		t.Fatalf("Expected 2 trackpoints within the workout, got %d", len(activity.Lap.Trackpoints))
+	}
+	if activity.Lap.Trackpoints[0].HeartRate != 120 || activity.Lap.Trackpoints[0].Time != "2024-01-06T14:15:00Z" {
+		t.Errorf("Expected trackpoints in time order, got %+v", activity.Lap.Trackpoints)
+	}
+	if activity.Lap.AverageHeartRate != 130 || activity.Lap.MaximumHeartRate != 140 {
+		t.Errorf("Expected average 130 and maximum 140, got %d and %d", activity.Lap.AverageHeartRate, activity.Lap.MaximumHeartRate)
+	}
+}
+
+func TestClient_WriteWorkoutTCX(t *testing.T) {
+	start := time.Date(2024, 1, 6, 14, 14, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		page := go_oura.HeartRates{Items: []go_oura.HeartRate{{Bpm: 120, Source: "workout", Timestamp: start.Add(time.Minute)}}, NextToken: "next"}
+		if req.URL.Query().Get("next_token") == "next" {
+			page = go_oura.HeartRates{Items: []go_oura.HeartRate{{Bpm: 130, Source: "workout", Timestamp: start.Add(2 * time.Minute)}}}
+		}
+		_ = json.NewEncoder(rw).Encode(page)
+	}))
+	defer server.Close()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	workout := go_oura.Workout{Id: "1", Activity: "cycling", StartDatetime: start, EndDatetime: start.Add(10 * time.Minute)}
+
+	var buf bytes.Buffer
+	if err := client.WriteWorkoutTCX(&buf, workout); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `Sport="Biking"`) || strings.Count(buf.String(), "<Trackpoint>") != 2 {
+		t.Errorf("Expected a biking activity with 2 trackpoints, got:\n%s", buf.String())
+	}
+}
+
+func TestTCXSport(t *testing.T) {
+	tests := []struct {
+		activity go_oura.WorkoutActivity
+		expected string
+	}{
+		{go_oura.WorkoutActivityRunning, "Running"},
+		{"Trail_Running", "Running"},
+		{"indoor_cycling", "Biking"},
+		{go_oura.WorkoutActivitySwimming, "Other"},
+		{"", "Other"},
+	}
+
+	for _, tt := range tests {
+		if sport := go_oura.TCXSport(tt.activity); sport != tt.expected {
+			t.Errorf("Expected %v for %q, got %v", tt.expected, tt.activity, sport)
+		}
+	}
+}
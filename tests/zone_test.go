@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/austinmoody/go_oura"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDate_In(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		day           go_oura.Date
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			name:          "standard time",
+			day:           go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+			expectedStart: "2024-01-21T00:00:00-05:00",
+			expectedEnd:   "2024-01-22T00:00:00-05:00",
+		},
+		{
+			name:          "daylight saving time starts",
+			day:           go_oura.Date{Time: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+			expectedStart: "2024-03-10T00:00:00-05:00",
+			expectedEnd:   "2024-03-11T00:00:00-04:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.day.StartOfDay(newYork), tt.day.EndOfDay(newYork)
+			if start.Format(time.RFC3339) != tt.expectedStart || end.Format(time.RFC3339) != tt.expectedEnd {
+				t.Errorf("Expected %s - %s, got %s - %s", tt.expectedStart, tt.expectedEnd, start.Format(time.RFC3339), end.Format(time.RFC3339))
+			}
+			if !tt.day.MidnightIn(newYork).Equal(start) {
+				t.Errorf("Expected MidnightIn to equal StartOfDay, got %v", tt.day.MidnightIn(newYork))
+			}
+			// In is still time.Time.In, the same instant in another location.
+			if !tt.day.In(newYork).Equal(tt.day.Time) {
+				t.Errorf("Expected In to keep the instant, got %v", tt.day.In(newYork))
+			}
+		})
+	}
+}
+
+func TestDateOf(t *testing.T) {
+	timestamp := time.Date(2024, 1, 21, 22, 0, 0, 0, time.FixedZone("", -5*60*60))
+
+	if day := go_oura.DateOf(timestamp); day.Format("2006-01-02") != "2024-01-21" {
+		t.Errorf("Expected 2024-01-21, got %s", day.Format("2006-01-02"))
+	}
+	if day := go_oura.DateOf(timestamp.UTC()); day.Format("2006-01-02") != "2024-01-22" {
+		t.Errorf("Expected 2024-01-22, got %s", day.Format("2006-01-02"))
+	}
+}
+
+func TestZoneModel_Location(t *testing.T) {
+	day := func(d int) go_oura.Date { return go_oura.Date{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)} }
+	newYork := time.FixedZone("", -5*60*60)
+	berlin := time.FixedZone("", 60*60)
+
+	zones := go_oura.NewZoneModel(
+		go_oura.Sleeps{Items: []go_oura.Sleep{
+			{Day: day(10), BedtimeEnd: time.Date(2024, 1, 10, 7, 0, 0, 0, newYork)},
+			{Day: day(14), BedtimeEnd: time.Date(2024, 1, 14, 7, 0, 0, 0, berlin)},
+		}},
+		go_oura.Workout{Day: day(14), StartDatetime: time.Date(2024, 1, 14, 9, 0, 0, 0, berlin)},
+		go_oura.Workout{Day: day(14), StartDatetime: time.Date(2024, 1, 14, 3, 0, 0, 0, newYork)},
+		go_oura.SleepTime{Day: day(20), OptimalBedtime: &go_oura.OptimalBedtime{DayTz: -18000}},
+	)
+
+	tests := []struct {
+		name     string
+		day      go_oura.Date
+		expected int
+	}{
+		{name: "observed", day: day(10), expected: -18000},
+		{name: "most observed", day: day(14), expected: 3600},
+		{name: "closest earlier day", day: day(16), expected: 3600},
+		{name: "before first observation", day: day(1), expected: -18000},
+		{name: "sleep time zone", day: day(20), expected: -18000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, offset := tt.day.MidnightIn(zones.Location(tt.day)).Zone()
+			if offset != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, offset)
+			}
+		})
+	}
+
+	start, end := zones.DayRange(day(14))
+	if start.Format(time.RFC3339) != "2024-01-14T00:00:00+01:00" || end.Sub(start) != 24*time.Hour {
+		t.Errorf("Unexpected day range %v - %v", start, end)
+	}
+
+	// Day 13 is in New York and day 14 in Berlin, the range of day 13 ends where day 14 starts.
+	_, end13 := zones.DayRange(day(13))
+	start14, _ := zones.DayRange(day(14))
+	if !end13.Equal(start14) || end13.Format(time.RFC3339) != "2024-01-14T00:00:00+01:00" {
+		t.Errorf("Expected day 13 to end at %v, got %v", start14, end13)
+	}
+
+	// Days observed out of order are still searched in order.
+	zones.Observe(day(5), 7200)
+	if offset, ok := zones.Offset(day(7)); !ok || offset != 7200 {
+		t.Errorf("Expected %d, got %d", 7200, offset)
+	}
+	if offset, ok := zones.Offset(day(12)); !ok || offset != -18000 {
+		t.Errorf("Expected %d, got %d", -18000, offset)
+	}
+
+	if (&go_oura.ZoneModel{}).Location(day(1)) != time.UTC {
+		t.Errorf("Expected UTC for an empty model")
+	}
+}
+
+func TestClient_GetHeartRatesForDays(t *testing.T) {
+	location := time.FixedZone("", -5*60*60)
+
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		query = map[string]string{
+			"start_datetime": req.URL.Query().Get("start_datetime"),
+			"end_datetime":   req.URL.Query().Get("end_datetime"),
+		}
+		_ = json.NewEncoder(rw).Encode(go_oura.HeartRates{Items: []go_oura.HeartRate{
+			{Bpm: 60, Source: "sleep", Timestamp: time.Date(2024, 1, 21, 5, 0, 0, 0, time.UTC)},
+			{Bpm: 70, Source: "awake", Timestamp: time.Date(2024, 1, 23, 5, 0, 0, 0, time.UTC)},
+		}})
+	}))
+	defer server.Close()
+
+	client := go_oura.NewClientWithUrlAndHttp("", server.URL, server.Client())
+	day := go_oura.Date{Time: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}
+
+	heartRates, err := client.GetHeartRatesForDays(day, go_oura.Date{Time: day.AddDate(0, 0, 1)}, location)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if query["start_datetime"] != "2024-01-21T00:00:00-05:00" || query["end_datetime"] != "2024-01-23T00:00:00-05:00" {
+		t.Errorf("Unexpected query %v", query)
+	}
+	if len(heartRates) != 1 || heartRates[0].Bpm != 60 {
+		t.Errorf("Expected heart rates outside the days to be dropped, got %v", heartRates)
+	}
+}
@@ -16,6 +16,9 @@ func checkJSONFields(t reflect.Type, data []byte) error {
 	requiredFields := make([]string, 0, t.NumField())
 	for i := 0; i < t.NumField(); i++ {
 		jsonTag := t.Field(i).Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
 		requiredFields = append(requiredFields, jsonTag)
 	}
 
@@ -23,16 +23,16 @@ type Workouts struct {
 // Workout stores specifics for a single recorded workout
 // JSON described at https://cloud.ouraring.com/v2/docs#operation/Single_workout_Document_v2_usercollection_workout__document_id__get
 type Workout struct {
-	Id            string    `json:"id"`
-	Activity      string    `json:"activity"`
-	Calories      float64   `json:"calories"`
-	Day           Date      `json:"day"`
-	Distance      float64   `json:"distance"`
-	EndDatetime   time.Time `json:"end_datetime"`
-	Intensity     string    `json:"intensity"`
-	Label         string    `json:"label"`
-	Source        string    `json:"source"`
-	StartDatetime time.Time `json:"start_datetime"`
+	Id            string           `json:"id"`
+	Activity      WorkoutActivity  `json:"activity"`
+	Calories      float64          `json:"calories"`
+	Day           Date             `json:"day"`
+	Distance      float64          `json:"distance"`
+	EndDatetime   time.Time        `json:"end_datetime"`
+	Intensity     WorkoutIntensity `json:"intensity"`
+	Label         string           `json:"label"`
+	Source        WorkoutSource    `json:"source"`
+	StartDatetime time.Time        `json:"start_datetime"`
 }
 
 type workoutBase Workout
@@ -0,0 +1,141 @@
+// This file contains a model of the user's time zone per day, inferred from the offsets of document timestamps.
+//
+// Day fields are calendar days in the user's time zone, while the API only exposes that zone as the offset of
+// timestamps such as Sleep.BedtimeStart.  Heart rate timestamps are returned in UTC and are not used.
+
+package go_oura
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ZoneModel holds the UTC offsets observed for each day.  The zero value is an empty model.
+type ZoneModel struct {
+	offsets map[string]map[int]int
+	// days holds the keys of offsets in order.
+	days []string
+}
+
+// NewZoneModel returns a ZoneModel with the offsets of documents added.
+func NewZoneModel(documents ...any) *ZoneModel {
+	zones := &ZoneModel{}
+	zones.Add(documents...)
+	return zones
+}
+
+// Add observes the offsets of documents, which may be single documents or the lists returned by the client.
+func (z *ZoneModel) Add(documents ...any) {
+	for _, document := range documents {
+		for _, d := range expandDocuments(document) {
+			switch d := d.(type) {
+			case DailyActivity:
+				z.ObserveTime(d.Day, d.Timestamp)
+			case DailyReadiness:
+				z.ObserveTime(d.Day, d.Timestamp)
+			case DailySleep:
+				z.ObserveTime(d.Day, d.Timestamp)
+			case EnhancedTag:
+				if d.StartDay != nil && d.StartTime != nil {
+					z.ObserveTime(*d.StartDay, *d.StartTime)
+				}
+			case RestMode:
+				z.ObserveTime(d.StartDay, d.StartTime)
+			case Session:
+				z.ObserveTime(d.Day, d.StartDatetime)
+			case Sleep:
+				z.ObserveTime(d.Day, d.BedtimeEnd)
+			case SleepTime:
+				if d.OptimalBedtime != nil {
+					z.Observe(d.Day, d.OptimalBedtime.DayTz)
+				}
+			case Workout:
+				z.ObserveTime(d.Day, d.StartDatetime)
+			}
+		}
+	}
+}
+
+// ObserveTime records the offset of t for day.  Zero times are ignored.
+func (z *ZoneModel) ObserveTime(day Date, t time.Time) {
+	if day.IsZero() || t.IsZero() {
+		return
+	}
+	_, offset := t.Zone()
+	z.Observe(day, offset)
+}
+
+// Observe records offset, in seconds east of UTC, for day.
+func (z *ZoneModel) Observe(day Date, offset int) {
+	if z.offsets == nil {
+		z.offsets = make(map[string]map[int]int)
+	}
+
+	key := day.Format("2006-01-02")
+	if z.offsets[key] == nil {
+		z.offsets[key] = make(map[int]int)
+		i := sort.SearchStrings(z.days, key)
+		z.days = slices.Insert(z.days, i, key)
+	}
+	z.offsets[key][offset]++
+}
+
+// Offset returns the offset most often observed for day.  Days without observations take the offset of the
+// closest earlier day, or the closest later day when there is no earlier one.  The second value is false when the
+// model has no observations at all.
+func (z *ZoneModel) Offset(day Date) (int, bool) {
+	if len(z.offsets) == 0 {
+		return 0, false
+	}
+
+	key := day.Format("2006-01-02")
+	if counts, ok := z.offsets[key]; ok {
+		return mostObservedOffset(counts), true
+	}
+
+	i := sort.SearchStrings(z.days, key)
+	if i > 0 {
+		return mostObservedOffset(z.offsets[z.days[i-1]]), true
+	}
+	return mostObservedOffset(z.offsets[z.days[0]]), true
+}
+
+// Location returns a fixed zone with the offset of day, or UTC when the model has no observations.
+func (z *ZoneModel) Location(day Date) *time.Location {
+	offset, ok := z.Offset(day)
+	if !ok {
+		return time.UTC
+	}
+	return fixedZone(offset)
+}
+
+// DayRange returns the first instant of day and the first instant of the following day, each in the user's zone on
+// that day, so the ranges of consecutive days tile even when the offset changes between them.
+func (z *ZoneModel) DayRange(day Date) (time.Time, time.Time) {
+	next := Date{Time: day.AddDate(0, 0, 1)}
+	return day.StartOfDay(z.Location(day)), next.StartOfDay(z.Location(next))
+}
+
+func mostObservedOffset(counts map[int]int) int {
+	best, bestCount := 0, -1
+	for offset, count := range counts {
+		if count > bestCount || (count == bestCount && offset < best) {
+			best, bestCount = offset, count
+		}
+	}
+	return best
+}
+
+func fixedZone(offset int) *time.Location {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+	}
+	abs := offset
+	if abs < 0 {
+		abs = -abs
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%c%02d:%02d", sign, abs/3600, abs%3600/60), offset)
+}